@@ -0,0 +1,148 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// ─── Admin Cache ─────────────────────────
+var (
+	adminCache    = make(map[int64]adminList) // chatID -> cached administrators
+	openToggles   = make(map[int64]bool)      // chatID -> everyone may change settings
+	adminMutex    sync.RWMutex                // protects adminCache and openToggles
+	adminCacheTTL = 10 * time.Minute
+)
+
+type adminList struct {
+	ids     map[int64]bool
+	fetched time.Time
+}
+
+// chatAdmins returns the administrator IDs of a chat, refreshing the cached
+// list through getChatAdministrators once it is older than adminCacheTTL.
+func chatAdmins(bot *tgbotapi.BotAPI, chatID int64) (map[int64]bool, error) {
+	adminMutex.RLock()
+	cached, ok := adminCache[chatID]
+	adminMutex.RUnlock()
+	if ok && time.Since(cached.fetched) < adminCacheTTL {
+		return cached.ids, nil
+	}
+
+	members, err := bot.GetChatAdministrators(tgbotapi.ChatAdministratorsConfig{
+		ChatConfig: tgbotapi.ChatConfig{ChatID: chatID},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("get chat administrators: %w", err)
+	}
+
+	ids := make(map[int64]bool, len(members))
+	for _, m := range members {
+		if m.User != nil && (m.Status == "creator" || m.Status == "administrator") {
+			ids[m.User.ID] = true
+		}
+	}
+
+	adminMutex.Lock()
+	adminCache[chatID] = adminList{ids: ids, fetched: time.Now()}
+	adminMutex.Unlock()
+	log.Printf(ColorCyan+"👮 Cached %d admins for chat %d"+ColorReset, len(ids), chatID)
+	return ids, nil
+}
+
+// isChatAdmin reports whether the sender of msg administers the chat.
+// Anonymous admins post as the group itself via sender_chat.
+func isChatAdmin(bot *tgbotapi.BotAPI, msg *tgbotapi.Message) bool {
+	if !isGroup(msg.Chat) {
+		return true
+	}
+	if msg.SenderChat != nil && msg.SenderChat.ID == msg.Chat.ID {
+		return true
+	}
+	if msg.From == nil {
+		return false
+	}
+	return isUserAdmin(bot, msg.Chat.ID, msg.From.ID)
+}
+
+// isUserAdmin is the lookup behind isChatAdmin for updates that carry a
+// user rather than a message, such as button presses.
+func isUserAdmin(bot *tgbotapi.BotAPI, chatID, userID int64) bool {
+	admins, err := chatAdmins(bot, chatID)
+	if err != nil {
+		logError("chatAdmins", bot.Self.UserName, err)
+		return false
+	}
+	return admins[userID]
+}
+
+func isOpenToggle(chatID int64) bool {
+	adminMutex.RLock()
+	defer adminMutex.RUnlock()
+	return openToggles[chatID]
+}
+
+func setOpenToggle(chatID int64, open bool) {
+	adminMutex.Lock()
+	defer adminMutex.Unlock()
+	openToggles[chatID] = open
+	log.Printf(ColorBlue+"🔓 Open toggles %s for chat %d"+ColorReset,
+		map[bool]string{true: "ENABLED", false: "DISABLED"}[open], chatID)
+}
+
+// requireAdmin gates settings commands. It replies to the sender and
+// returns false when they are not allowed to change this chat.
+func requireAdmin(bot *tgbotapi.BotAPI, msg *tgbotapi.Message) bool {
+	if isOpenToggle(msg.Chat.ID) || isChatAdmin(bot, msg) {
+		return true
+	}
+
+	log.Printf(ColorYellow+"🚫 Non-admin @%s tried /%s in %d"+ColorReset, msg.From.UserName, msg.Command(), msg.Chat.ID)
+	cfg := tgbotapi.NewMessage(msg.Chat.ID, T(langFor(msg), "error.admin_only"))
+	cfg.ReplyToMessageID = msg.MessageID
+	if _, err := bot.Send(cfg); err != nil {
+		logError("requireAdmin", bot.Self.UserName, err)
+	}
+	return false
+}
+
+// ─── /everyone Command ───────────────────
+// handleEveryoneCommand lets admins allow every member to use settings
+// commands. The override itself always stays admin-only.
+func handleEveryoneCommand(bot *tgbotapi.BotAPI, msg *tgbotapi.Message) {
+	if !isChatAdmin(bot, msg) {
+		cfg := tgbotapi.NewMessage(msg.Chat.ID, T(langFor(msg), "error.admin_only"))
+		cfg.ReplyToMessageID = msg.MessageID
+		if _, err := bot.Send(cfg); err != nil {
+			logError("everyoneDenied", bot.Self.UserName, err)
+		}
+		return
+	}
+
+	lang := langFor(msg)
+	var text string
+	switch strings.ToLower(strings.TrimSpace(msg.CommandArguments())) {
+	case "on":
+		setOpenToggle(msg.Chat.ID, true)
+		text = T(lang, "everyone.on")
+	case "off":
+		setOpenToggle(msg.Chat.ID, false)
+		text = T(lang, "everyone.off")
+	default:
+		state := T(lang, "everyone.admins")
+		if isOpenToggle(msg.Chat.ID) {
+			state = T(lang, "everyone.everyone")
+		}
+		text = T(lang, "everyone.status", state)
+	}
+
+	cfg := tgbotapi.NewMessage(msg.Chat.ID, text)
+	cfg.ParseMode = "HTML"
+	if _, err := bot.Send(cfg); err != nil {
+		logError("everyoneCommand", bot.Self.UserName, err)
+	}
+}
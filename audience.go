@@ -0,0 +1,305 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// ─── Broadcast Audiences ─────────────────
+// "/broadcast type=group,supergroup lang=es active=7d" narrows the next
+// broadcast. Filters combine with AND, values within one filter with OR:
+//
+//	type=private,group,supergroup,channel
+//	lang=en,es         the chat's /lang choice, else a private user's
+//	                   Telegram language, else defaultLang
+//	active=7d          heard from within the window (m, h or d)
+//	reactions=on|off   group reaction status; private chats count as on
+//	tag=vip,beta       owner-assigned with /tag
+//	bot=name           chats that bot is in, and only it delivers
+type audience struct {
+	Types        map[string]bool
+	Langs        map[string]bool
+	ActiveWithin time.Duration
+	Reactions    string // "", "on" or "off"
+	Tags         []string
+	Bot          *tgbotapi.BotAPI
+}
+
+// broadcastDraft is what the owner has set up for the next broadcast.
+type broadcastDraft struct {
+	Audience audience
+	Filters  string    // the filters as typed, stored with schedules
+	Schedule *schedule // set with /schedule, nil to send right away
+}
+
+var broadcastDrafts = make(map[int64]*broadcastDraft) // ownerID -> next broadcast, guarded by mutex
+
+func parseAudience(args string) (audience, error) {
+	var a audience
+	for _, field := range strings.Fields(strings.ToLower(args)) {
+		key, value, ok := strings.Cut(field, "=")
+		if !ok || value == "" {
+			return a, fmt.Errorf("expected key=value, got %q", field)
+		}
+		values := strings.Split(value, ",")
+		switch key {
+		case "type":
+			a.Types = make(map[string]bool)
+			for _, v := range values {
+				switch v {
+				case "private", "group", "supergroup", "channel":
+					a.Types[v] = true
+				default:
+					return a, fmt.Errorf("unknown chat type %q", v)
+				}
+			}
+		case "lang":
+			a.Langs = make(map[string]bool)
+			for _, v := range values {
+				lang := normalizeLang(v)
+				if lang == "" {
+					return a, fmt.Errorf("unknown language %q", v)
+				}
+				a.Langs[lang] = true
+			}
+		case "active":
+			d, err := parseWindow(value)
+			if err != nil {
+				return a, err
+			}
+			a.ActiveWithin = d
+		case "reactions":
+			if value != "on" && value != "off" {
+				return a, fmt.Errorf("reactions must be on or off")
+			}
+			a.Reactions = value
+		case "tag":
+			a.Tags = values
+		case "bot":
+			bot := findBot(strings.TrimPrefix(value, "@"))
+			if bot == nil {
+				return a, fmt.Errorf("no bot named %q", value)
+			}
+			a.Bot = bot
+		default:
+			return a, fmt.Errorf("unknown filter %q", key)
+		}
+	}
+	return a, nil
+}
+
+// parseWindow reads durations like 30m, 12h or 7d.
+func parseWindow(s string) (time.Duration, error) {
+	if n, ok := strings.CutSuffix(s, "d"); ok {
+		days, err := strconv.Atoi(n)
+		if err != nil || days <= 0 {
+			return 0, fmt.Errorf("bad window %q", s)
+		}
+		return time.Duration(days) * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("bad window %q", s)
+	}
+	return d, nil
+}
+
+func findBot(username string) *tgbotapi.BotAPI {
+	botMutex.RLock()
+	defer botMutex.RUnlock()
+	for _, bot := range botInstances {
+		if strings.EqualFold(bot.Self.UserName, username) {
+			return bot
+		}
+	}
+	return nil
+}
+
+func (a audience) String() string {
+	var parts []string
+	if len(a.Types) > 0 {
+		parts = append(parts, "type="+joinKeys(a.Types))
+	}
+	if len(a.Langs) > 0 {
+		parts = append(parts, "lang="+joinKeys(a.Langs))
+	}
+	if a.ActiveWithin > 0 {
+		parts = append(parts, "active="+a.ActiveWithin.String())
+	}
+	if a.Reactions != "" {
+		parts = append(parts, "reactions="+a.Reactions)
+	}
+	if len(a.Tags) > 0 {
+		parts = append(parts, "tag="+strings.Join(a.Tags, ","))
+	}
+	if a.Bot != nil {
+		parts = append(parts, "bot=@"+a.Bot.Self.UserName)
+	}
+	if len(parts) == 0 {
+		return "everyone"
+	}
+	return strings.Join(parts, " ")
+}
+
+func joinKeys(m map[string]bool) string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return strings.Join(keys, ",")
+}
+
+// recipients returns the active subscribers matching a.
+func (a audience) recipients() ([]int64, error) {
+	var tagged map[int64]bool
+	if len(a.Tags) > 0 {
+		var err error
+		if tagged, err = chatsTagged(a.Tags); err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	var ids []int64
+	subMutex.RLock()
+	for id, sub := range subscribers {
+		switch {
+		case sub.Inactive,
+			len(a.Types) > 0 && !a.Types[sub.Type],
+			a.ActiveWithin > 0 && now.Sub(sub.LastSeen) > a.ActiveWithin,
+			a.Bot != nil && !sub.Bots[a.Bot.Self.ID],
+			tagged != nil && !tagged[id]:
+			continue
+		}
+		ids = append(ids, id)
+	}
+	subMutex.RUnlock()
+
+	// Language and reaction status live behind other locks
+	out := ids[:0]
+	for _, id := range ids {
+		if len(a.Langs) > 0 && !a.Langs[chatLanguage(id)] {
+			continue
+		}
+		if a.Reactions != "" && areReactionsEnabled(id) != (a.Reactions == "on") {
+			continue
+		}
+		out = append(out, id)
+	}
+	return out, nil
+}
+
+// chatLanguage is the language replies in chatID use: the /lang choice,
+// then the Telegram language of a private chat's user, then defaultLang.
+// It matches langFor for private chats.
+func chatLanguage(chatID int64) string {
+	langMutex.RLock()
+	lang, ok := chatLanguages[chatID]
+	langMutex.RUnlock()
+	if ok {
+		return lang
+	}
+	subMutex.RLock()
+	lang = subscribers[chatID].Lang
+	subMutex.RUnlock()
+	if lang != "" {
+		return lang
+	}
+	return defaultLang
+}
+
+// ─── Chat Tags ───────────────────────────
+func chatsTagged(tags []string) (map[int64]bool, error) {
+	query := `SELECT DISTINCT chat_id FROM chat_tags WHERE tag IN (?` + strings.Repeat(", ?", len(tags)-1) + `)`
+	args := make([]interface{}, len(tags))
+	for i, t := range tags {
+		args[i] = t
+	}
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query tags: %w", err)
+	}
+	defer rows.Close()
+	out := make(map[int64]bool)
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scan tag: %w", err)
+		}
+		out[id] = true
+	}
+	return out, rows.Err()
+}
+
+// handleTagCommand handles "/tag <chat_id> <tag>..." and lists tag counts
+// without arguments. "/untag <chat_id> <tag>..." removes tags.
+func handleTagCommand(c *commandContext) {
+	args := strings.Fields(strings.ToLower(c.Msg.CommandArguments()))
+	remove := strings.EqualFold(c.Msg.Command(), "untag")
+
+	var text string
+	switch {
+	case len(args) == 0 && !remove:
+		text = tagSummary(c.Lang)
+	case len(args) < 2:
+		text = T(c.Lang, "tag.usage", c.Msg.Command())
+	default:
+		chatID, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			text = T(c.Lang, "tag.bad_chat", args[0])
+			break
+		}
+		stmt := `INSERT OR IGNORE INTO chat_tags (chat_id, tag) VALUES (?, ?)`
+		if remove {
+			stmt = `DELETE FROM chat_tags WHERE chat_id = ? AND tag = ?`
+		}
+		for _, tag := range args[1:] {
+			if _, err := db.Exec(stmt, chatID, tag); err != nil {
+				logError("tagCommand", c.Bot.Self.UserName, err)
+				return
+			}
+		}
+		log.Printf(ColorBlue+"🏷️  /%s %d %v"+ColorReset, c.Msg.Command(), chatID, args[1:])
+		text = T(c.Lang, "tag.updated", chatID, strings.Join(args[1:], ", "))
+	}
+
+	if _, err := c.Bot.Send(tgbotapi.NewMessage(c.Msg.Chat.ID, text)); err != nil {
+		logError("tagCommand", c.Bot.Self.UserName, err)
+	}
+}
+
+func tagSummary(lang string) string {
+	rows, err := db.Query(`SELECT tag, COUNT(*) FROM chat_tags GROUP BY tag ORDER BY tag`)
+	if err != nil {
+		logError("tagSummary", "", err)
+		return T(lang, "tag.error")
+	}
+	defer rows.Close()
+
+	var b strings.Builder
+	b.WriteString(T(lang, "tag.title") + "\n\n")
+	count := 0
+	for rows.Next() {
+		var (
+			tag string
+			n   int
+		)
+		if err := rows.Scan(&tag, &n); err != nil {
+			logError("tagSummary", "", err)
+			return T(lang, "tag.error")
+		}
+		b.WriteString(T(lang, "tag.entry", tag, n) + "\n")
+		count++
+	}
+	if count == 0 {
+		b.WriteString(T(lang, "tag.empty"))
+	}
+	return b.String()
+}
@@ -80,7 +80,8 @@ var (
 	db           *sql.DB
 	failCount    = make(map[string]int)
 	mutex        sync.Mutex
-	subscribers  = make(map[int64]struct{})   // chats to broadcast to
+	subscribers  = make(map[int64]subscriber) // chats to broadcast to
+	subMutex     sync.RWMutex                 // protects subscribers
 	broadcastMap = make(map[int64]bool)       // ownerID -> awaiting next msg
 	ownerID      = int64(5290407067)          // only this ID can broadcast
 
@@ -89,7 +90,8 @@ var (
 
 	// Group reaction control
 	groupReactions = make(map[int64]bool)     // chatID -> reactions enabled/disabled
-	reactionMutex  sync.RWMutex              // protects groupReactions
+	topicReactions = make(map[topicKey]bool)  // forum topic -> override of the chat default
+	reactionMutex  sync.RWMutex              // protects groupReactions and topicReactions
 
 	emojis = []string{
 		"❤️", "👍", "🔥", "🥰", "👏", "😁", "🤔", "🤯", "😱", "🤬", "😢", "🎉",
@@ -109,6 +111,16 @@ func main() {
 	if err := initDB(); err != nil {
 		log.Fatalf(ColorFatal+"💥 DB init failed: %v"+ColorReset, err)
 	}
+	if err := loadSubscribers(); err != nil {
+		log.Fatalf(ColorFatal+"💥 Loading subscribers failed: %v"+ColorReset, err)
+	}
+	if err := loadChatSettings(); err != nil {
+		log.Fatalf(ColorFatal+"💥 Loading chat settings failed: %v"+ColorReset, err)
+	}
+
+	if err := loadTemplates(); err != nil {
+		log.Fatalf(ColorFatal+"💥 Template load failed: %v"+ColorReset, err)
+	}
 
 	tokens := strings.Split(os.Getenv("BOT_TOKENS"), ",")
 	if len(tokens) == 0 || tokens[0] == "" {
@@ -121,6 +133,9 @@ func main() {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
+	go watchTemplates(ctx)
+	go runScheduler(ctx)
+
 	for _, token := range tokens {
 		token = strings.TrimSpace(token)
 		if token == "" {
@@ -130,7 +145,8 @@ func main() {
 		go func(tok string) {
 			defer wg.Done()
 			if err := runBot(ctx, tok); err != nil {
-				logError("runBot", tok, err)
+				botID, _, _ := strings.Cut(tok, ":")
+				logError("runBot", "bot "+botID, err)
 			}
 		}(token)
 	}
@@ -168,12 +184,75 @@ func areReactionsEnabled(chatID int64) bool {
 
 func setReactionsEnabled(chatID int64, enabled bool) {
 	reactionMutex.Lock()
-	defer reactionMutex.Unlock()
 	groupReactions[chatID] = enabled
+	reactionMutex.Unlock()
+	storeChatReactions(chatID, enabled)
 	log.Printf(ColorBlue+"🎛️  Reactions %s for chat %d"+ColorReset, 
 		map[bool]string{true: "ENABLED", false: "DISABLED"}[enabled], chatID)
 }
 
+// ─── Topic Reaction Status ───────────────
+// topicKey addresses a forum topic. Thread 0 is the chat itself.
+type topicKey struct {
+	chatID   int64
+	threadID int
+}
+
+// areTopicReactionsEnabled falls back to the chat default when the topic
+// has no setting of its own.
+func areTopicReactionsEnabled(chatID int64, threadID int) bool {
+	if threadID != 0 {
+		reactionMutex.RLock()
+		enabled, exists := topicReactions[topicKey{chatID, threadID}]
+		reactionMutex.RUnlock()
+		if exists {
+			return enabled
+		}
+	}
+	return areReactionsEnabled(chatID)
+}
+
+func setTopicReactionsEnabled(chatID int64, threadID int, enabled bool) {
+	if threadID == 0 {
+		setReactionsEnabled(chatID, enabled)
+		return
+	}
+	reactionMutex.Lock()
+	topicReactions[topicKey{chatID, threadID}] = enabled
+	reactionMutex.Unlock()
+	storeTopicReactions(chatID, threadID, enabled)
+	log.Printf(ColorBlue+"🎛️  Reactions %s for topic %d in chat %d"+ColorReset,
+		map[bool]string{true: "ENABLED", false: "DISABLED"}[enabled], threadID, chatID)
+}
+
+// resetTopicReactions sets the chat default and drops every topic override
+// so all topics inherit it again.
+func resetTopicReactions(chatID int64, enabled bool) {
+	reactionMutex.Lock()
+	for key := range topicReactions {
+		if key.chatID == chatID {
+			delete(topicReactions, key)
+		}
+	}
+	reactionMutex.Unlock()
+	clearTopicReactions(chatID)
+	setReactionsEnabled(chatID, enabled)
+}
+
+// applyReactionToggle stores a /begin or /end. "all" as argument sets the
+// chat default for every topic, otherwise a topic only changes itself.
+func applyReactionToggle(msg *tgbotapi.Message, threadID int, enabled bool) string {
+	if strings.EqualFold(strings.TrimSpace(msg.CommandArguments()), "all") {
+		resetTopicReactions(msg.Chat.ID, enabled)
+		return "group"
+	}
+	setTopicReactionsEnabled(msg.Chat.ID, threadID, enabled)
+	if threadID != 0 {
+		return "topic"
+	}
+	return "group"
+}
+
 // ─── Dummy Server ────────────────────────
 func startDummyServer() {
 	port := getEnv("PORT", "10000")
@@ -201,15 +280,202 @@ func initDB() error {
 		chat_id INTEGER,
 		message_id INTEGER,
 		emoji TEXT,
-		timestamp DATETIME DEFAULT CURRENT_TIMESTAMP
+		timestamp DATETIME DEFAULT CURRENT_TIMESTAMP,
+		user_id INTEGER,
+		bot_id INTEGER
 	);`)
 	if err != nil {
 		return fmt.Errorf("create table: %w", err)
 	}
+	_, err = db.Exec(`CREATE TABLE IF NOT EXISTS media_cache (
+		bot_id INTEGER NOT NULL,
+		name TEXT NOT NULL,
+		mod_time INTEGER NOT NULL,
+		size INTEGER NOT NULL,
+		file_id TEXT NOT NULL,
+		uploaded_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY (bot_id, name)
+	);`)
+	if err != nil {
+		return fmt.Errorf("create media_cache: %w", err)
+	}
+	// Databases created before user_id existed need the column added
+	if err := addColumnIfMissing("reactions", "user_id", "INTEGER"); err != nil {
+		return err
+	}
+	if err := addColumnIfMissing("reactions", "bot_id", "INTEGER"); err != nil {
+		return err
+	}
+	_, err = db.Exec(`CREATE TABLE IF NOT EXISTS subscribers (
+		chat_id INTEGER PRIMARY KEY,
+		type TEXT NOT NULL,
+		title TEXT NOT NULL DEFAULT '',
+		first_seen DATETIME NOT NULL,
+		last_seen DATETIME NOT NULL,
+		inactive INTEGER NOT NULL DEFAULT 0,
+		lang TEXT NOT NULL DEFAULT ''
+	);
+	CREATE TABLE IF NOT EXISTS subscriber_bots (
+		chat_id INTEGER NOT NULL,
+		bot_id INTEGER NOT NULL,
+		first_seen DATETIME NOT NULL,
+		last_seen DATETIME NOT NULL,
+		PRIMARY KEY (chat_id, bot_id)
+	);`)
+	if err != nil {
+		return fmt.Errorf("create subscribers: %w", err)
+	}
+	_, err = db.Exec(`CREATE TABLE IF NOT EXISTS broadcast_jobs (
+		id INTEGER PRIMARY KEY,
+		origin_bot_id INTEGER NOT NULL,
+		from_chat_id INTEGER NOT NULL,
+		message_id INTEGER NOT NULL,
+		text TEXT NOT NULL DEFAULT '',
+		entities TEXT NOT NULL DEFAULT 'null',
+		lang TEXT NOT NULL DEFAULT 'en',
+		status TEXT NOT NULL,
+		progress_chat_id INTEGER NOT NULL DEFAULT 0,
+		progress_message_id INTEGER NOT NULL DEFAULT 0,
+		via_bot_id INTEGER NOT NULL DEFAULT 0,
+		silent INTEGER NOT NULL DEFAULT 0,
+		pin INTEGER NOT NULL DEFAULT 0,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		finished_at DATETIME
+	);
+	CREATE TABLE IF NOT EXISTS broadcast_deliveries (
+		job_id INTEGER NOT NULL,
+		chat_id INTEGER NOT NULL,
+		status TEXT NOT NULL,
+		bot_id INTEGER NOT NULL DEFAULT 0,
+		error TEXT NOT NULL DEFAULT '',
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY (job_id, chat_id)
+	);`)
+	if err != nil {
+		return fmt.Errorf("create broadcast jobs: %w", err)
+	}
+	if err := addColumnIfMissing("subscribers", "lang", "TEXT NOT NULL DEFAULT ''"); err != nil {
+		return err
+	}
+	if err := addColumnIfMissing("broadcast_jobs", "via_bot_id", "INTEGER NOT NULL DEFAULT 0"); err != nil {
+		return err
+	}
+	_, err = db.Exec(`CREATE TABLE IF NOT EXISTS scheduled_broadcasts (
+		id INTEGER PRIMARY KEY,
+		origin_bot_id INTEGER NOT NULL,
+		from_chat_id INTEGER NOT NULL,
+		message_id INTEGER NOT NULL,
+		text TEXT NOT NULL DEFAULT '',
+		entities TEXT NOT NULL DEFAULT 'null',
+		lang TEXT NOT NULL DEFAULT 'en',
+		filters TEXT NOT NULL DEFAULT '',
+		spec TEXT NOT NULL,
+		next_run INTEGER NOT NULL,
+		active INTEGER NOT NULL DEFAULT 1,
+		recurring INTEGER NOT NULL DEFAULT 0,
+		silent INTEGER NOT NULL DEFAULT 0,
+		pin INTEGER NOT NULL DEFAULT 0,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);`)
+	if err != nil {
+		return fmt.Errorf("create scheduled_broadcasts: %w", err)
+	}
+	for _, table := range []string{"broadcast_jobs", "scheduled_broadcasts"} {
+		for _, column := range []string{"silent", "pin"} {
+			if err := addColumnIfMissing(table, column, "INTEGER NOT NULL DEFAULT 0"); err != nil {
+				return err
+			}
+		}
+	}
+	if err := addColumnIfMissing("scheduled_broadcasts", "recurring", "INTEGER NOT NULL DEFAULT 0"); err != nil {
+		return err
+	}
+	// Rows from before the column existed only had the spec to go by
+	_, err = db.Exec(`UPDATE scheduled_broadcasts SET recurring = 1
+		WHERE recurring = 0 AND (spec LIKE 'daily %' OR spec LIKE 'weekly %' OR spec LIKE 'every %')`)
+	if err != nil {
+		return fmt.Errorf("backfill recurring schedules: %w", err)
+	}
+	_, err = db.Exec(`CREATE TABLE IF NOT EXISTS chat_settings (
+		chat_id INTEGER PRIMARY KEY,
+		lang TEXT NOT NULL DEFAULT '',
+		reactions INTEGER
+	);`)
+	if err != nil {
+		return fmt.Errorf("create chat_settings: %w", err)
+	}
+	_, err = db.Exec(`CREATE TABLE IF NOT EXISTS chat_topic_settings (
+		chat_id INTEGER NOT NULL,
+		thread_id INTEGER NOT NULL,
+		reactions INTEGER NOT NULL,
+		PRIMARY KEY (chat_id, thread_id)
+	);`)
+	if err != nil {
+		return fmt.Errorf("create chat_topic_settings: %w", err)
+	}
+	_, err = db.Exec(`CREATE TABLE IF NOT EXISTS message_reactions (
+		chat_id INTEGER NOT NULL,
+		message_id INTEGER NOT NULL,
+		actor_id INTEGER NOT NULL,
+		count INTEGER NOT NULL,
+		PRIMARY KEY (chat_id, message_id, actor_id)
+	);`)
+	if err != nil {
+		return fmt.Errorf("create message_reactions: %w", err)
+	}
+	_, err = db.Exec(`CREATE TABLE IF NOT EXISTS chat_tags (
+		chat_id INTEGER NOT NULL,
+		tag TEXT NOT NULL,
+		PRIMARY KEY (chat_id, tag)
+	);`)
+	if err != nil {
+		return fmt.Errorf("create chat_tags: %w", err)
+	}
+	_, err = db.Exec(`CREATE INDEX IF NOT EXISTS idx_reactions_chat_time ON reactions (chat_id, timestamp);
+		CREATE INDEX IF NOT EXISTS idx_reactions_time ON reactions (timestamp);
+		CREATE INDEX IF NOT EXISTS idx_reactions_chat_user ON reactions (chat_id, user_id);`)
+	if err != nil {
+		return fmt.Errorf("create indexes: %w", err)
+	}
 	log.Println(ColorGreen + "📦 SQLite DB initialized" + ColorReset)
 	return nil
 }
 
+// ─── DB Migrations ───────────────────────
+// addColumnIfMissing upgrades tables created by older versions in place.
+func addColumnIfMissing(table, column, decl string) error {
+	rows, err := db.Query(fmt.Sprintf(`PRAGMA table_info(%s)`, table))
+	if err != nil {
+		return fmt.Errorf("table info %s: %w", table, err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			cid       int
+			name, typ string
+			notNull   int
+			dflt      sql.NullString
+			pk        int
+		)
+		if err := rows.Scan(&cid, &name, &typ, &notNull, &dflt, &pk); err != nil {
+			return fmt.Errorf("scan table info %s: %w", table, err)
+		}
+		if name == column {
+			return nil
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("table info %s: %w", table, err)
+	}
+	rows.Close()
+
+	if _, err := db.Exec(fmt.Sprintf(`ALTER TABLE %s ADD COLUMN %s %s`, table, column, decl)); err != nil {
+		return fmt.Errorf("add column %s.%s: %w", table, column, err)
+	}
+	log.Printf(ColorGreen+"🛠️  Migrated %s: added column %s"+ColorReset, table, column)
+	return nil
+}
+
 // ─── Bot Runner ──────────────────────────
 func runBot(ctx context.Context, token string) error {
 	log.Println(ColorBlue + "🔑 Creating bot instance..." + ColorReset)
@@ -224,17 +490,14 @@ func runBot(ctx context.Context, token string) error {
 
 	log.Printf(ColorGreen+"🤖 Bot launched: @%s [%d]"+ColorReset, bot.Self.UserName, bot.Self.ID)
 
-	bot.Request(tgbotapi.NewSetMyCommands(
-		tgbotapi.BotCommand{Command: "start", Description: "Show welcome message"},
-		tgbotapi.BotCommand{Command: "begin", Description: "Start reactions in group"},
-		tgbotapi.BotCommand{Command: "end", Description: "Stop reactions in group"},
-	))
+	registerCommands(bot)
+	resumeBroadcasts(bot)
 
 	u := tgbotapi.NewUpdate(0)
 	u.Timeout = 20
-	u.AllowedUpdates = []string{"message"}
+	u.AllowedUpdates = []string{"message", "message_reaction", "message_reaction_count", "callback_query", "inline_query", "my_chat_member"}
 
-	updates := bot.GetUpdatesChan(u)
+	updates := pollUpdates(ctx, bot, u)
 	log.Println(ColorCyan + "📡 Polling updates…" + ColorReset)
 
 	for {
@@ -247,238 +510,109 @@ func runBot(ctx context.Context, token string) error {
 				log.Printf(ColorYellow+"📥 Received message from @%s: %s"+ColorReset, update.Message.From.UserName, update.Message.Text)
 				go handleUpdate(bot, update)
 			}
+			if update.MessageReaction != nil {
+				go recordHumanReaction(update.MessageReaction)
+				go handleReactionUpdate(bot, update.MessageReaction)
+			}
+			if update.MessageReactionCount != nil {
+				go recordReactionCount(update.MessageReactionCount)
+			}
+			if update.CallbackQuery != nil {
+				go handleCallback(bot, update.CallbackQuery, update.ThreadID)
+			}
+			if update.InlineQuery != nil {
+				go handleInlineQuery(bot, update.InlineQuery)
+			}
+			if update.MyChatMember != nil {
+				go handleMyChatMember(bot, update.MyChatMember)
+			}
 		}
 	}
 }
 
 // ─── Update Handler ──────────────────────
-func handleUpdate(localBot *tgbotapi.BotAPI, update tgbotapi.Update) {
+func handleUpdate(localBot *tgbotapi.BotAPI, update botUpdate) {
 	msg := update.Message
+	threadID := update.ThreadID
 	if msg == nil || msg.From == nil {
 		log.Println(ColorRed + "⚠️  Skipping empty update or nil sender" + ColorReset)
 		return
 	}
 
 	log.Printf(ColorCyan+"📝 Handling message: %s"+ColorReset, msg.Text)
-	subscribers[msg.Chat.ID] = struct{}{}
-	log.Printf(ColorBlue+"📊 Subscribers count: %d"+ColorReset, len(subscribers))
-
-	// secret /broadcast activation
-	if msg.IsCommand() && msg.Command() == "broadcast" && msg.From.ID == ownerID {
-		mutex.Lock()
-		broadcastMap[ownerID] = true
-		mutex.Unlock()
-		log.Printf(ColorBlue+"🚀 Broadcast mode activated by @%s via bot %s"+ColorReset, msg.From.UserName, localBot.Self.UserName)
-		cfg := tgbotapi.NewMessage(msg.Chat.ID,
-			"🚀 *Broadcast Mode Activated!* 🚀\n\nSend any content now and I'll forward it via ALL bots to all subscribers.\n\nTo cancel, send /cancelbroadcast")
-		cfg.ParseMode = "Markdown"
-		if _, err := localBot.Send(cfg); err != nil {
-			logError("broadcastGuide", localBot.Self.UserName, err)
-		}
-		return
-	}
-
-	// cancel broadcast
-	if msg.IsCommand() && msg.Command() == "cancelbroadcast" && msg.From.ID == ownerID {
-		mutex.Lock()
-		broadcastMap[ownerID] = false
-		mutex.Unlock()
-		log.Printf(ColorBlue+"🛑 Broadcast mode deactivated by @%s via bot %s"+ColorReset, msg.From.UserName, localBot.Self.UserName)
-		cfg := tgbotapi.NewMessage(msg.Chat.ID, "🛑 *Broadcast Mode Deactivated.*")
-		cfg.ParseMode = "Markdown"
-		if _, err := localBot.Send(cfg); err != nil {
-			logError("cancelBroadcast", localBot.Self.UserName, err)
-		}
-		return
-	}
 
-	// broadcast payload - check if owner is in broadcast mode
-	mutex.Lock()
-	isBroadcasting := broadcastMap[ownerID]
-	mutex.Unlock()
-	
-	if isBroadcasting && msg.From.ID == ownerID {
-		log.Printf(ColorBlue+"📢 Broadcasting message from @%s via bot %s to all subscribers..."+ColorReset, msg.From.UserName, localBot.Self.UserName)
-		
-		var successCount, failCount int
-		botMutex.RLock()
-		for _, bot := range botInstances {
-			for chatID := range subscribers {
-				copy := tgbotapi.NewCopyMessage(chatID, msg.Chat.ID, msg.MessageID)
-				if _, err := bot.Send(copy); err != nil {
-					log.Printf(ColorRed+"❌ [%s] to %d failed: %v"+ColorReset, bot.Self.UserName, chatID, err)
-					failCount++
-				} else {
-					log.Printf(ColorGreen+"✅ [%s] sent to %d"+ColorReset, bot.Self.UserName, chatID)
-					successCount++
-				}
-			}
+	// A group turned supergroup: carry its settings over to the new ID
+	if from, to, ok := migrationOf(msg); ok {
+		if err := migrateChat(from, to); err != nil {
+			logError("migrateChat", localBot.Self.UserName, err)
 		}
-		botMutex.RUnlock()
-		
-		// Send broadcast summary
-		summary := fmt.Sprintf("📊 *Broadcast Complete!*\n\n✅ Successful: %d\n❌ Failed: %d\n🤖 Total Bots: %d\n👥 Total Subscribers: %d", 
-			successCount, failCount, len(botInstances), len(subscribers))
-		cfg := tgbotapi.NewMessage(msg.Chat.ID, summary)
-		cfg.ParseMode = "Markdown"
-		if _, err := localBot.Send(cfg); err != nil {
-			logError("broadcastSummary", localBot.Self.UserName, err)
+		if msg.Chat.ID == to {
+			addSubscriber(localBot.Self.ID, msg.Chat, msg.From)
 		}
-		
-		// Auto-deactivate broadcast mode after sending
-		mutex.Lock()
-		broadcastMap[ownerID] = false
-		mutex.Unlock()
-		log.Println(ColorBlue + "🛑 Broadcast mode auto-deactivated after sending" + ColorReset)
 		return
 	}
 
-	// /begin command - only for groups
-	if msg.IsCommand() && msg.Command() == "begin" {
-		if !isGroup(msg.Chat) {
-			cfg := tgbotapi.NewMessage(msg.Chat.ID, "❌ This command only works in groups!")
-			if _, err := localBot.Send(cfg); err != nil {
-				logError("beginPrivateError", localBot.Self.UserName, err)
-			}
-			return
-		}
-		
-		setReactionsEnabled(msg.Chat.ID, true)
-		
-		// React to the command first
-		go reactToMessage(localBot, msg)
-		
-		// Send confirmation with random image
-		randomImage := SAKURA_IMAGES[rand.Intn(len(SAKURA_IMAGES))]
-		
-		kb := tgbotapi.NewInlineKeyboardMarkup(
-			tgbotapi.NewInlineKeyboardRow(
-				tgbotapi.NewInlineKeyboardButtonURL("Updates", channelURL),
-				tgbotapi.NewInlineKeyboardButtonURL("Support", groupURL),
-			),
-		)
-		
-		message := "💖 Reactions Started! 💖\n\n" +
-			"I'm now actively reacting to messages in this group with fun emojis! ✨\n\n" +
-			"Use /end to stop reactions anytime.\n\n" +
-			"<i>Let's make this chat more lively! 💞</i>"
-		
-		photo := tgbotapi.NewPhoto(msg.Chat.ID, tgbotapi.FileURL(randomImage))
-		photo.Caption = message
-		photo.ParseMode = "HTML"
-		photo.ReplyMarkup = kb
-		
-		if _, err := localBot.Send(photo); err != nil {
-			log.Printf(ColorRed+"❌ Failed to send begin photo, falling back to text: %v"+ColorReset, err)
-			cfg := tgbotapi.NewMessage(msg.Chat.ID, message)
-			cfg.ParseMode = "HTML"
-			cfg.ReplyMarkup = kb
-			if _, err := localBot.Send(cfg); err != nil {
-				logError("beginFallback", localBot.Self.UserName, err)
-			}
-		}
-		
-		log.Printf(ColorGreen+"✅ Reactions enabled for group %d"+ColorReset, msg.Chat.ID)
+	subCount := addSubscriber(localBot.Self.ID, msg.Chat, msg.From)
+	log.Printf(ColorBlue+"📊 Subscribers count: %d"+ColorReset, subCount)
+
+	// The owner's next message after /broadcast goes out to everyone
+	if handleBroadcastPayload(localBot, msg) {
 		return
 	}
 
-	// /end command - only for groups
-	if msg.IsCommand() && msg.Command() == "end" {
-		if !isGroup(msg.Chat) {
-			cfg := tgbotapi.NewMessage(msg.Chat.ID, "❌ This command only works in groups!")
-			if _, err := localBot.Send(cfg); err != nil {
-				logError("endPrivateError", localBot.Self.UserName, err)
-			}
-			return
-		}
-		
-		setReactionsEnabled(msg.Chat.ID, false)
-		
-		cfg := tgbotapi.NewMessage(msg.Chat.ID, 
-			"👋 Reactions Stopped! 👋\n\n"+
-			"I've stopped reacting to messages in this group.\n\n"+
-			"Use /begin to start reactions again! ✨")
-		cfg.ParseMode = "HTML"
-		
-		if _, err := localBot.Send(cfg); err != nil {
-			logError("endCommand", localBot.Self.UserName, err)
-		}
-		
-		log.Printf(ColorYellow+"🛑 Reactions disabled for group %d"+ColorReset, msg.Chat.ID)
+	if dispatchCommand(localBot, msg, threadID) {
 		return
 	}
 
-	// /start command - different behavior for groups vs private
-	if msg.IsCommand() && msg.Command() == "start" {
-		if isGroup(msg.Chat) {
-			// Group start command with image
-			sendGroupWelcome(localBot, msg)
-		} else {
-			// Private start command (original behavior)
-			go reactToMessage(localBot, msg)
-			sendWelcome(localBot, msg)
-		}
+	// Run the chat's filter chain before reacting
+	if reason := filteredBy(msg); reason != "" {
+		log.Printf(ColorYellow+"🧹 Skipping reaction for msg %d in chat %d (filter: %s)"+ColorReset, msg.MessageID, msg.Chat.ID, reason)
 		return
 	}
 
-	// /ping command - react first, then respond
-	if msg.IsCommand() && msg.Command() == "ping" {
-		// Only react if reactions are enabled or in private chat
-		if !isGroup(msg.Chat) || areReactionsEnabled(msg.Chat.ID) {
-			go reactToMessage(localBot, msg)
-		}
-		
-		log.Println(ColorBlue + "🏓 /ping command received" + ColorReset)
-		start := time.Now()
-
-		// Create initial message - reply in groups, simple message in private
-		var cfg tgbotapi.MessageConfig
-		if isGroup(msg.Chat) {
-			// In groups: reply to the original message
-			cfg = tgbotapi.NewMessage(msg.Chat.ID, "🛰️ Pinging...")
-			cfg.ReplyToMessageID = msg.MessageID
-		} else {
-			// In private: send simple message without reply
-			cfg = tgbotapi.NewMessage(msg.Chat.ID, "🛰️ Pinging...")
-		}
-
-		sentMsg, err := localBot.Send(cfg)
-		if err != nil {
-			logError("pingSend", localBot.Self.UserName, err)
-			return
-		}
-
-		elapsed := float64(time.Since(start).Microseconds()) / 1000 // ms
-		latency := fmt.Sprintf("%.2fms", elapsed)
-
-		text := fmt.Sprintf("🏓 [Pong\\!](https://t.me/SoulMeetsHQ) %s", escapeMarkdownV2(latency))
-		edit := tgbotapi.NewEditMessageText(msg.Chat.ID, sentMsg.MessageID, text)
-		edit.ParseMode = "MarkdownV2"
-		edit.DisableWebPagePreview = true
-
-		if _, err := localBot.Send(edit); err != nil {
-			logError("pingEdit", localBot.Self.UserName, err)
-		} else {
-			log.Printf(ColorGreen+"⚡ Ping responded in %s"+ColorReset, latency)
-		}
+	// Quiet hours and reaction rate
+	if reason := pacingSkip(msg.Chat.ID); reason != "" {
+		log.Printf(ColorYellow+"🌙 Skipping reaction for msg %d in chat %d (%s)"+ColorReset, msg.MessageID, msg.Chat.ID, reason)
 		return
 	}
 
 	// React to regular messages based on chat type and settings
 	if !isGroup(msg.Chat) {
 		// Always react in private chats
-		reactToMessage(localBot, msg)
-	} else if areReactionsEnabled(msg.Chat.ID) {
-		// Only react in groups if reactions are enabled
-		reactToMessage(localBot, msg)
+		reactOrMirror(localBot, msg)
+	} else if areTopicReactionsEnabled(msg.Chat.ID, threadID) {
+		// Only react in groups if reactions are enabled for this topic
+		reactOrMirror(localBot, msg)
 	} else {
 		log.Printf(ColorYellow+"⏸️  Skipping reaction for group %d (reactions disabled)"+ColorReset, msg.Chat.ID)
 	}
 }
 
+// ─── Callback Handler ────────────────────
+func handleCallback(localBot *tgbotapi.BotAPI, cb *tgbotapi.CallbackQuery, threadID int) {
+	if cb.Message == nil || cb.From == nil {
+		log.Println(ColorRed + "⚠️  Skipping callback without message or sender" + ColorReset)
+		return
+	}
+
+	switch {
+	case strings.HasPrefix(cb.Data, settingsPrefix):
+		handleSettingsCallback(localBot, cb, threadID)
+	case strings.HasPrefix(cb.Data, adminPrefix):
+		handleAdminCallback(localBot, cb)
+	case strings.HasPrefix(cb.Data, presetPrefix):
+		handlePresetCallback(localBot, cb)
+	case strings.HasPrefix(cb.Data, broadcastPrefix):
+		handleBroadcastCallback(localBot, cb)
+	default:
+		answerCallback(localBot, cb.ID, "")
+	}
+}
+
 // ─── Escape helper ───────────────────────
 func escapeMarkdownV2(s string) string {
 	replacer := strings.NewReplacer(
+		"\\", "\\\\",
 		"_", "\\_",
 		"*", "\\*",
 		"[", "\\[",
@@ -503,38 +637,30 @@ func escapeMarkdownV2(s string) string {
 
 // ─── Group Welcome Sender ────────────────
 func sendGroupWelcome(bot *tgbotapi.BotAPI, msg *tgbotapi.Message) {
-	log.Printf(ColorBlue+"👋 Group /start by @%s in %d"+ColorReset, msg.From.UserName, msg.Chat.ID)
-
-	// Select a random Sakura image
-	randomImage := SAKURA_IMAGES[rand.Intn(len(SAKURA_IMAGES))]
-	log.Printf(ColorCyan+"🌸 Selected random Sakura image for group: %s"+ColorReset, randomImage)
+	log.Printf(ColorBlue+"👋 Group welcome for @%s in %d"+ColorReset, msg.From.UserName, msg.Chat.ID)
+	markWelcomed(msg.Chat.ID)
 
+	lang := langFor(msg)
 	kb := tgbotapi.NewInlineKeyboardMarkup(
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonURL("Updates", channelURL),
-			tgbotapi.NewInlineKeyboardButtonURL("Support", groupURL),
+			tgbotapi.NewInlineKeyboardButtonURL(T(lang, "btn.updates"), channelURL),
+			tgbotapi.NewInlineKeyboardButtonURL(T(lang, "btn.support"), groupURL),
 		),
 	)
 
-	message := "❤️ Hello Everyone! I'm <b>ReactionBot</b>!\n\n" +
-		"I'm here to make your group more fun with automatic emoji reactions! ✨\n\n" +
-		"📋 <b>Group Commands:</b>\n" +
-		"• /begin - Start reactions\n" +
-		"• /end - Stop reactions\n" +
-		"• /ping - Check my response time\n\n" +
-		"<i>Ready to bring some life to your conversations! 💞</i>"
+	message, mode := renderTemplate("welcome_group", lang, templateData{Chat: msg.Chat, User: msg.From, Bot: bot})
 
-	// Send photo with caption
-	photo := tgbotapi.NewPhoto(msg.Chat.ID, tgbotapi.FileURL(randomImage))
+	// Send photo with caption, picked from the media library
+	photo := tgbotapi.NewPhoto(msg.Chat.ID, nil)
 	photo.Caption = message
-	photo.ParseMode = "HTML"
+	photo.ParseMode = mode
 	photo.ReplyMarkup = kb
 
-	if _, err := bot.Send(photo); err != nil {
+	if _, err := sendLibraryPhoto(bot, photo); err != nil {
 		log.Printf(ColorRed+"❌ Failed to send group photo, falling back to text: %v"+ColorReset, err)
 		// Fallback to text message if photo fails
 		cfg := tgbotapi.NewMessage(msg.Chat.ID, message)
-		cfg.ParseMode = "HTML"
+		cfg.ParseMode = mode
 		cfg.ReplyMarkup = kb
 		if _, err := bot.Send(cfg); err != nil {
 			logError("sendGroupWelcome fallback", bot.Self.UserName, err)
@@ -548,36 +674,22 @@ func sendGroupWelcome(bot *tgbotapi.BotAPI, msg *tgbotapi.Message) {
 func sendWelcome(bot *tgbotapi.BotAPI, msg *tgbotapi.Message) {
 	log.Printf(ColorBlue+"👋 Private /start by @%s in %d"+ColorReset, msg.From.UserName, msg.Chat.ID)
 
-	// Select a random Sakura image
-	randomImage := SAKURA_IMAGES[rand.Intn(len(SAKURA_IMAGES))]
-	log.Printf(ColorCyan+"🌸 Selected random Sakura image for private: %s"+ColorReset, randomImage)
-
-	kb := tgbotapi.NewInlineKeyboardMarkup(
-		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonURL("Updates", channelURL),
-			tgbotapi.NewInlineKeyboardButtonURL("Support", groupURL),
-		),
-		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonURL("Add Me To Your Group", fmt.Sprintf("https://t.me/%s?startgroup=true", bot.Self.UserName)),
-		),
-	)
+	lang := langFor(msg)
+	kb := welcomeKeyboard(bot, lang)
 
-	message := "👋 Hey there! I'm <b>ReactionBot</b>.\n\n" +
-		"I automatically react to messages in your group with fun and random emojis like ❤️🔥🎉👌.\n\n" +
-		"Just add me to your group and enjoy the reactions!\n\n" +
-		"<i>P.S. I work best when I have a little admin magic 😉</i>"
+	message, mode := renderTemplate("welcome_private", lang, templateData{Chat: msg.Chat, User: msg.From, Bot: bot})
 
-	// Send photo with caption
-	photo := tgbotapi.NewPhoto(msg.Chat.ID, tgbotapi.FileURL(randomImage))
+	// Send photo with caption, picked from the media library
+	photo := tgbotapi.NewPhoto(msg.Chat.ID, nil)
 	photo.Caption = message
-	photo.ParseMode = "HTML"
+	photo.ParseMode = mode
 	photo.ReplyMarkup = kb
 
-	if _, err := bot.Send(photo); err != nil {
+	if _, err := sendLibraryPhoto(bot, photo); err != nil {
 		log.Printf(ColorRed+"❌ Failed to send private photo, falling back to text: %v"+ColorReset, err)
 		// Fallback to text message if photo fails
 		cfg := tgbotapi.NewMessage(msg.Chat.ID, message)
-		cfg.ParseMode = "HTML"
+		cfg.ParseMode = mode
 		cfg.ReplyMarkup = kb
 		if _, err := bot.Send(cfg); err != nil {
 			logError("sendWelcome fallback", bot.Self.UserName, err)
@@ -589,12 +701,28 @@ func sendWelcome(bot *tgbotapi.BotAPI, msg *tgbotapi.Message) {
 
 // ─── Emoji Reactor ───────────────────────
 func reactToMessage(bot *tgbotapi.BotAPI, msg *tgbotapi.Message) {
-	emoji := emojis[rand.Intn(len(emojis))]
-	log.Printf(ColorYellow+"✨ Reacting to msg %d in chat %d with %s"+ColorReset, msg.MessageID, msg.Chat.ID, emoji)
+	emoji := pickEmoji(msg.Chat.ID)
+	sendReaction(bot, msg.Chat.ID, msg.MessageID, msg.From.ID, emoji)
+}
+
+// reactOrMirror reacts right away, or defers to the first human reaction
+// when the chat is in mirror mode.
+func reactOrMirror(bot *tgbotapi.BotAPI, msg *tgbotapi.Message) {
+	if getReactionMode(msg.Chat.ID) == ModeMirror {
+		scheduleMirror(bot, msg)
+		return
+	}
+	reactToMessage(bot, msg)
+}
+
+// sendReaction sets emoji on a message. senderID is the author of the
+// message and only used for statistics.
+func sendReaction(bot *tgbotapi.BotAPI, chatID int64, msgID int, senderID int64, emoji string) {
+	log.Printf(ColorYellow+"✨ Reacting to msg %d in chat %d with %s"+ColorReset, msgID, chatID, emoji)
 
 	payload := map[string]interface{}{
-		"chat_id":    msg.Chat.ID,
-		"message_id": msg.MessageID,
+		"chat_id":    chatID,
+		"message_id": msgID,
 		"reaction":   []map[string]string{{"type": "emoji", "emoji": emoji}},
 	}
 
@@ -604,22 +732,25 @@ func reactToMessage(bot *tgbotapi.BotAPI, msg *tgbotapi.Message) {
 	resp, err := http.Post(url, "application/json", bytes.NewReader(body))
 	if err != nil {
 		logError("reaction POST", bot.Self.UserName, err)
+		incrementFailure(bot.Self.UserName)
 		return
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode == 200 {
-		log.Printf(ColorGreen+"✅ Reacted to msg %d in chat %d"+ColorReset, msg.MessageID, msg.Chat.ID)
-		logReaction(msg.Chat.ID, msg.MessageID, emoji)
+		log.Printf(ColorGreen+"✅ Reacted to msg %d in chat %d"+ColorReset, msgID, chatID)
+		logReaction(bot.Self.ID, chatID, msgID, senderID, emoji)
 	} else {
 		log.Printf(ColorRed+"⚠️ Reaction failed: %d"+ColorReset, resp.StatusCode)
+		incrementFailure(bot.Self.UserName)
 	}
 }
 
 // ─── DB Logger ───────────────────────────
-func logReaction(chatID int64, msgID int, emoji string) {
+func logReaction(botID, chatID int64, msgID int, userID int64, emoji string) {
 	log.Printf(ColorCyan+"🗄️  Logging reaction %s for msg %d in chat %d"+ColorReset, emoji, msgID, chatID)
-	_, err := db.Exec(`INSERT INTO reactions (chat_id, message_id, emoji) VALUES (?, ?, ?)`, chatID, msgID, emoji)
+	_, err := db.Exec(`INSERT INTO reactions (chat_id, message_id, user_id, bot_id, emoji) VALUES (?, ?, ?, ?, ?)`,
+		chatID, msgID, userID, botID, emoji)
 	if err != nil {
 		logError("SQLite Insert", "logReaction", err)
 	}
@@ -628,9 +759,12 @@ func logReaction(chatID int64, msgID int, emoji string) {
 // ─── Error Logger ────────────────────────
 func logError(scope, context string, err error) {
 	log.Printf(ColorRed+"❌ [%s/%s] Error: %v"+ColorReset, scope, context, err)
+	rememberError(scope, context, err)
 }
 
 // ─── Failure Alert ───────────────────────
+// incrementFailure counts failed reactions and polls per bot for the
+// /admin dashboard.
 func incrementFailure(bot string) {
 	mutex.Lock()
 	defer mutex.Unlock()
@@ -0,0 +1,343 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// ─── Broadcast ───────────────────────────
+// handleBroadcastCommand arms broadcast mode: the owner's next message is
+// copied to every subscriber matching the audience filters given as
+// arguments, see audience.go.
+func handleBroadcastCommand(c *commandContext) {
+	aud, err := parseAudience(c.Msg.CommandArguments())
+	if err != nil {
+		cfg := tgbotapi.NewMessage(c.Msg.Chat.ID, T(c.Lang, "broadcast.bad_audience", err))
+		if _, err := c.Bot.Send(cfg); err != nil {
+			logError("broadcastGuide", c.Bot.Self.UserName, err)
+		}
+		return
+	}
+	matched, err := aud.recipients()
+	if err != nil {
+		logError("broadcastAudience", c.Bot.Self.UserName, err)
+		return
+	}
+
+	mutex.Lock()
+	broadcastMap[ownerID] = true
+	broadcastDrafts[ownerID] = &broadcastDraft{Audience: aud, Filters: strings.TrimSpace(c.Msg.CommandArguments())}
+	mutex.Unlock()
+	log.Printf(ColorBlue+"🚀 Broadcast mode activated by @%s via bot %s for %s (%d chats)"+ColorReset,
+		c.Msg.From.UserName, c.Bot.Self.UserName, aud, len(matched))
+
+	text := T(c.Lang, "broadcast.activated") + "\n\n" +
+		T(c.Lang, "broadcast.audience", aud, len(matched), len(subscriberIDs()))
+	cfg := tgbotapi.NewMessage(c.Msg.Chat.ID, text)
+	cfg.ParseMode = "Markdown"
+	if _, err := c.Bot.Send(cfg); err != nil {
+		logError("broadcastGuide", c.Bot.Self.UserName, err)
+	}
+}
+
+func handleCancelBroadcastCommand(c *commandContext) {
+	mutex.Lock()
+	broadcastMap[ownerID] = false
+	delete(broadcastDrafts, ownerID)
+	mutex.Unlock()
+	log.Printf(ColorBlue+"🛑 Broadcast mode deactivated by @%s via bot %s"+ColorReset, c.Msg.From.UserName, c.Bot.Self.UserName)
+	cfg := tgbotapi.NewMessage(c.Msg.Chat.ID, T(c.Lang, "broadcast.deactivated"))
+	cfg.ParseMode = "Markdown"
+	if _, err := c.Bot.Send(cfg); err != nil {
+		logError("cancelBroadcast", c.Bot.Self.UserName, err)
+	}
+}
+
+// handleBroadcastPayload previews msg for confirmation when the owner is
+// in broadcast mode, see preview.go. It returns false when msg is not a
+// broadcast payload. Only private chats count: what the owner writes in
+// a group stays a normal message and broadcast mode remains armed.
+func handleBroadcastPayload(localBot *tgbotapi.BotAPI, msg *tgbotapi.Message) bool {
+	if msg.From.ID != ownerID || !msg.Chat.IsPrivate() {
+		return false
+	}
+	// Commands like /schedule and /cancelbroadcast are not payloads
+	if msg.IsCommand() && commandByName[strings.ToLower(msg.Command())] != nil {
+		return false
+	}
+	mutex.Lock()
+	isBroadcasting := broadcastMap[ownerID]
+	draft := broadcastDrafts[ownerID]
+	// Auto-deactivate broadcast mode, one payload per /broadcast
+	broadcastMap[ownerID] = false
+	delete(broadcastDrafts, ownerID)
+	mutex.Unlock()
+	if !isBroadcasting {
+		return false
+	}
+	if draft == nil {
+		draft = &broadcastDraft{}
+	}
+	previewBroadcast(localBot, msg, draft)
+	return true
+}
+
+// startBroadcast sends msg to the draft's audience right away.
+func startBroadcast(localBot *tgbotapi.BotAPI, msg *tgbotapi.Message, draft *broadcastDraft, opts broadcastOptions) {
+	log.Printf(ColorBlue+"📢 Broadcasting message from @%s via bot %s to all subscribers..."+ColorReset, msg.From.UserName, localBot.Self.UserName)
+
+	recipients, err := draft.Audience.recipients()
+	if err != nil {
+		logError("broadcastAudience", localBot.Self.UserName, err)
+		return
+	}
+	job, err := createBroadcastJob(localBot, msg, langFor(msg), recipients, draft.Audience.Bot, opts)
+	if err != nil {
+		logError("createBroadcastJob", localBot.Self.UserName, err)
+		return
+	}
+	go job.run()
+}
+
+// ─── Broadcast Jobs ──────────────────────
+// A job fans out to a pool of workers; each bot's rate limiter keeps the
+// pool within Telegram's limits. Every chat gets a single message, so
+// the per-chat limit is never reached. The owner sees a progress message
+// that is edited while the job runs and turns into the summary at the end.
+// Jobs and their deliveries are stored as they go, see jobs.go.
+const (
+	broadcastWorkers  = 8
+	broadcastProgress = 3 * time.Second // how often the progress message is edited
+)
+
+// broadcastOptions are picked on the preview and kept with the job.
+type broadcastOptions struct {
+	Silent bool // deliver without a notification sound
+	Pin    bool // pin the message where the bot may
+}
+
+type broadcastJob struct {
+	id       int64
+	origin   *tgbotapi.BotAPI // received the payload and reports progress
+	msg      *tgbotapi.Message
+	lang     string
+	total    int
+	pending  []int64          // recipients not attempted yet
+	progress tgbotapi.Message // the owner's progress message, if sent
+	viaBotID int64            // when set, only this bot delivers
+	opts     broadcastOptions
+
+	started   time.Time
+	doneStart int64 // deliveries finished before this run, for the ETA
+	sent      atomic.Int64
+	failed    atomic.Int64
+}
+
+var (
+	runningJobs = make(map[int64]*broadcastJob) // jobID -> job, while it runs
+	jobsMutex   sync.Mutex                      // protects runningJobs
+)
+
+// broadcastsInFlight counts running jobs and their deliveries still to go.
+func broadcastsInFlight() (jobs int, left int64) {
+	jobsMutex.Lock()
+	defer jobsMutex.Unlock()
+	for _, j := range runningJobs {
+		left += int64(j.total) - j.sent.Load() - j.failed.Load()
+	}
+	return len(runningJobs), left
+}
+
+// currentBots snapshots botInstances, so a resumed job can use bots that
+// started after it.
+func currentBots() []*tgbotapi.BotAPI {
+	botMutex.RLock()
+	defer botMutex.RUnlock()
+	return append([]*tgbotapi.BotAPI(nil), botInstances...)
+}
+
+// bots returns the bots allowed to deliver this job.
+func (j *broadcastJob) bots() []*tgbotapi.BotAPI {
+	bots := currentBots()
+	if j.viaBotID == 0 {
+		return bots
+	}
+	for _, bot := range bots {
+		if bot.Self.ID == j.viaBotID {
+			return []*tgbotapi.BotAPI{bot}
+		}
+	}
+	return nil
+}
+
+func (j *broadcastJob) run() {
+	j.started = time.Now()
+	jobsMutex.Lock()
+	runningJobs[j.id] = j
+	jobsMutex.Unlock()
+	defer func() {
+		jobsMutex.Lock()
+		delete(runningJobs, j.id)
+		jobsMutex.Unlock()
+	}()
+	j.doneStart = j.sent.Load() + j.failed.Load()
+	if j.progress.MessageID == 0 {
+		progress, err := j.origin.Send(tgbotapi.NewMessage(j.msg.Chat.ID, j.progressText()))
+		if err != nil {
+			logError("broadcastProgress", j.origin.Self.UserName, err)
+		} else {
+			j.progress = progress
+			saveJobProgress(j.id, progress)
+		}
+	}
+
+	queue := make(chan int64)
+	var wg sync.WaitGroup
+	for i := 0; i < broadcastWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for chatID := range queue {
+				markDelivery(j.id, chatID, deliverySending, 0, nil)
+				bot, err := deliverBroadcast(j.origin, j.bots(), chatID, j.msg, j.opts)
+				if err != nil {
+					log.Printf(ColorRed+"❌ Broadcast to %d failed: %v"+ColorReset, chatID, err)
+					markDelivery(j.id, chatID, deliveryFailed, 0, err)
+					j.failed.Add(1)
+				} else {
+					markDelivery(j.id, chatID, deliverySent, bot.Self.ID, nil)
+					j.sent.Add(1)
+				}
+			}
+		}()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		for _, chatID := range j.pending {
+			queue <- chatID
+		}
+		close(queue)
+		wg.Wait()
+		close(done)
+	}()
+
+	ticker := time.NewTicker(broadcastProgress)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			j.editProgress(j.progressText(), "")
+		case <-done:
+			finishJob(j.id)
+			summary := T(j.lang, "broadcast.summary", j.sent.Load(), j.failed.Load(), len(currentBots()), j.total)
+			if j.progress.MessageID == 0 {
+				cfg := tgbotapi.NewMessage(j.msg.Chat.ID, summary)
+				cfg.ParseMode = "Markdown"
+				if _, err := j.origin.Send(cfg); err != nil {
+					logError("broadcastSummary", j.origin.Self.UserName, err)
+				}
+			} else {
+				j.editProgress(summary, "Markdown")
+			}
+			log.Printf(ColorGreen+"📢 Broadcast #%d done in %s: %d sent, %d failed"+ColorReset,
+				j.id, time.Since(j.started).Round(time.Second), j.sent.Load(), j.failed.Load())
+			return
+		}
+	}
+}
+
+func (j *broadcastJob) progressText() string {
+	sent, failed := j.sent.Load(), j.failed.Load()
+	done := sent + failed
+	remaining := int64(j.total) - done
+
+	eta := "…"
+	if thisRun := done - j.doneStart; thisRun > 0 {
+		perChat := time.Since(j.started) / time.Duration(thisRun)
+		eta = (perChat * time.Duration(remaining)).Round(time.Second).String()
+	}
+	return T(j.lang, "broadcast.progress", sent, failed, remaining, eta)
+}
+
+func (j *broadcastJob) editProgress(text, parseMode string) {
+	if j.progress.MessageID == 0 {
+		return
+	}
+	edit := tgbotapi.NewEditMessageText(j.progress.Chat.ID, j.progress.MessageID, text)
+	edit.ParseMode = parseMode
+	if _, err := j.origin.Send(edit); err != nil && !strings.Contains(err.Error(), "message is not modified") {
+		logError("broadcastProgress", j.origin.Self.UserName, err)
+	}
+}
+
+// deliverBroadcast sends msg to chatID exactly once, through the first
+// member bot that manages to, and returns that bot. origin received msg
+// and goes first. Bots that turn out to be gone from the chat lose their
+// membership on the way. A failed pin does not fail the delivery.
+func deliverBroadcast(origin *tgbotapi.BotAPI, bots []*tgbotapi.BotAPI, chatID int64, msg *tgbotapi.Message, opts broadcastOptions) (*tgbotapi.BotAPI, error) {
+	members := memberBots(chatID, bots)
+	if len(members) == 0 {
+		return nil, errors.New("no member bot")
+	}
+	sort.SliceStable(members, func(i, j int) bool { return members[i] == origin && members[j] != origin })
+
+	var lastErr error
+	for _, bot := range members {
+		var sent tgbotapi.Message
+		cfg, err := broadcastConfig(origin, bot, chatID, msg, opts)
+		if err == nil {
+			sent, err = sendLimited(bot, cfg)
+		}
+		if err != nil {
+			log.Printf(ColorYellow+"⚠️  [%s] to %d failed: %v"+ColorReset, bot.Self.UserName, chatID, err)
+			if isMembershipError(err) {
+				removeSubscriberBot(bot.Self.ID, chatID)
+			}
+			lastErr = err
+			continue
+		}
+		log.Printf(ColorGreen+"✅ [%s] sent to %d"+ColorReset, bot.Self.UserName, chatID)
+		if opts.Pin {
+			pinBroadcast(bot, chatID, sent.MessageID, opts.Silent)
+		}
+		return bot, nil
+	}
+	return nil, lastErr
+}
+
+// broadcastConfig builds what bot sends for msg. Message and file IDs are
+// only valid for the bot that received them, so other bots can resend
+// text but not copy media.
+func broadcastConfig(origin, bot *tgbotapi.BotAPI, chatID int64, msg *tgbotapi.Message, opts broadcastOptions) (tgbotapi.Chattable, error) {
+	if bot == origin {
+		cfg := tgbotapi.NewCopyMessage(chatID, msg.Chat.ID, msg.MessageID)
+		cfg.DisableNotification = opts.Silent
+		return cfg, nil
+	}
+	if msg.Text == "" {
+		return nil, fmt.Errorf("only @%s can copy media", origin.Self.UserName)
+	}
+	cfg := tgbotapi.NewMessage(chatID, msg.Text)
+	cfg.Entities = msg.Entities
+	cfg.DisableNotification = opts.Silent
+	return cfg, nil
+}
+
+// pinBroadcast pins a delivered broadcast. Bots without pin rights in a
+// group just leave it unpinned.
+func pinBroadcast(bot *tgbotapi.BotAPI, chatID int64, messageID int, silent bool) {
+	limiterFor(bot.Self.ID).wait()
+	pin := tgbotapi.PinChatMessageConfig{ChatID: chatID, MessageID: messageID, DisableNotification: silent}
+	if _, err := bot.Request(pin); err != nil {
+		log.Printf(ColorYellow+"⚠️  [%s] could not pin in %d: %v"+ColorReset, bot.Self.UserName, chatID, err)
+	}
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+)
+
+// ─── Stored Chat Settings ────────────────
+// The /lang choice and the chat-wide reaction switch are kept in
+// chat_settings, so broadcast audiences and schedules filtering on them
+// still match after a restart. A NULL reactions column means the chat
+// never chose and keeps the default. Forum topic overrides live in
+// chat_topic_settings.
+func storeChatLanguage(chatID int64, lang string) {
+	_, err := db.Exec(`INSERT INTO chat_settings (chat_id, lang) VALUES (?, ?)
+		ON CONFLICT(chat_id) DO UPDATE SET lang = excluded.lang`, chatID, lang)
+	if err != nil {
+		logError("storeChatLanguage", fmt.Sprint(chatID), err)
+	}
+}
+
+func storeChatReactions(chatID int64, enabled bool) {
+	_, err := db.Exec(`INSERT INTO chat_settings (chat_id, reactions) VALUES (?, ?)
+		ON CONFLICT(chat_id) DO UPDATE SET reactions = excluded.reactions`, chatID, enabled)
+	if err != nil {
+		logError("storeChatReactions", fmt.Sprint(chatID), err)
+	}
+}
+
+func storeTopicReactions(chatID int64, threadID int, enabled bool) {
+	_, err := db.Exec(`INSERT INTO chat_topic_settings (chat_id, thread_id, reactions) VALUES (?, ?, ?)
+		ON CONFLICT(chat_id, thread_id) DO UPDATE SET reactions = excluded.reactions`, chatID, threadID, enabled)
+	if err != nil {
+		logError("storeTopicReactions", fmt.Sprint(chatID), err)
+	}
+}
+
+// clearTopicReactions drops a chat's topic overrides, see
+// resetTopicReactions.
+func clearTopicReactions(chatID int64) {
+	if _, err := db.Exec(`DELETE FROM chat_topic_settings WHERE chat_id = ?`, chatID); err != nil {
+		logError("clearTopicReactions", fmt.Sprint(chatID), err)
+	}
+}
+
+// loadChatSettings fills chatLanguages, groupReactions and topicReactions
+// at startup.
+func loadChatSettings() error {
+	rows, err := db.Query(`SELECT chat_id, lang, reactions FROM chat_settings`)
+	if err != nil {
+		return fmt.Errorf("query chat settings: %w", err)
+	}
+	defer rows.Close()
+
+	langs := make(map[int64]string)
+	reactions := make(map[int64]bool)
+	count := 0
+	for rows.Next() {
+		var (
+			chatID  int64
+			lang    string
+			enabled sql.NullBool
+		)
+		if err := rows.Scan(&chatID, &lang, &enabled); err != nil {
+			return fmt.Errorf("scan chat settings: %w", err)
+		}
+		if lang != "" {
+			langs[chatID] = lang
+		}
+		if enabled.Valid {
+			reactions[chatID] = enabled.Bool
+		}
+		count++
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("query chat settings: %w", err)
+	}
+
+	langMutex.Lock()
+	for id, lang := range langs {
+		chatLanguages[id] = lang
+	}
+	langMutex.Unlock()
+	topics, err := loadTopicReactions()
+	if err != nil {
+		return err
+	}
+
+	reactionMutex.Lock()
+	for id, enabled := range reactions {
+		groupReactions[id] = enabled
+	}
+	for key, enabled := range topics {
+		topicReactions[key] = enabled
+	}
+	reactionMutex.Unlock()
+	log.Printf(ColorGreen+"🌐 Loaded settings of %d chats and %d topics"+ColorReset, count, len(topics))
+	return nil
+}
+
+func loadTopicReactions() (map[topicKey]bool, error) {
+	rows, err := db.Query(`SELECT chat_id, thread_id, reactions FROM chat_topic_settings`)
+	if err != nil {
+		return nil, fmt.Errorf("query topic settings: %w", err)
+	}
+	defer rows.Close()
+
+	topics := make(map[topicKey]bool)
+	for rows.Next() {
+		var (
+			key     topicKey
+			enabled bool
+		)
+		if err := rows.Scan(&key.chatID, &key.threadID, &enabled); err != nil {
+			return nil, fmt.Errorf("scan topic settings: %w", err)
+		}
+		topics[key] = enabled
+	}
+	return topics, rows.Err()
+}
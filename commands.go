@@ -0,0 +1,428 @@
+package main
+
+import (
+	"fmt"
+	"html"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// ─── Command Registry ────────────────────
+// commandScope says where a command may be used.
+type commandScope int
+
+const (
+	ScopeAll     commandScope = iota // private chats and groups
+	ScopePrivate                     // private chats only
+	ScopeGroup                       // groups and supergroups only
+	ScopeOwner                       // the bot owner only, hidden from everyone else
+)
+
+// commandContext is what a command handler gets for one invocation.
+type commandContext struct {
+	Bot      *tgbotapi.BotAPI
+	Msg      *tgbotapi.Message
+	ThreadID int
+	Lang     string
+}
+
+type commandHandler func(c *commandContext)
+
+// command describes one slash command. The description comes from the
+// message catalog under "cmd.<Name>".
+type command struct {
+	Name        string
+	Scope       commandScope
+	AdminOnly   bool          // only chat admins, unless the chat opened toggles with /everyone
+	AlwaysAdmin bool          // only chat admins, even with /everyone on; the handler checks it
+	Cooldown    time.Duration // minimum time between uses per chat, 0 for none
+	Handler     commandHandler
+}
+
+// forAdmins reports whether cmd is for group admins only, so the plain
+// member menu leaves it out.
+func (cmd *command) forAdmins() bool {
+	return cmd.AdminOnly || cmd.AlwaysAdmin
+}
+
+// middleware wraps a handler for a specific command.
+type middleware func(cmd *command, next commandHandler) commandHandler
+
+var (
+	commands      []*command
+	commandByName = make(map[string]*command)
+
+	// middlewares run outermost first.
+	middlewares = []middleware{withRecover, withScope, withAdmin, withCooldown}
+)
+
+func init() {
+	commands = []*command{
+		{Name: "start", Scope: ScopeAll, Cooldown: 5 * time.Second, Handler: handleStartCommand},
+		{Name: "help", Scope: ScopeAll, Cooldown: 5 * time.Second, Handler: handleHelpCommand},
+		{Name: "begin", Scope: ScopeGroup, AdminOnly: true, Handler: handleBeginCommand},
+		{Name: "end", Scope: ScopeGroup, AdminOnly: true, Handler: handleEndCommand},
+		{Name: "ping", Scope: ScopeAll, Cooldown: 3 * time.Second, Handler: handlePingCommand},
+		{Name: "status", Scope: ScopeAll, Cooldown: 10 * time.Second, Handler: handleStatusCommand},
+		{Name: "settings", Scope: ScopeAll, AdminOnly: true, Handler: handleSettingsCommand},
+		{Name: "mode", Scope: ScopeAll, Handler: simple(handleModeCommand)},
+		{Name: "filter", Scope: ScopeAll, Handler: simple(handleFilterCommand)},
+		{Name: "lang", Scope: ScopeAll, Handler: simple(handleLangCommand)},
+		{Name: "everyone", Scope: ScopeGroup, AlwaysAdmin: true, Handler: simple(handleEveryoneCommand)},
+		{Name: "stats", Scope: ScopeGroup, Cooldown: 10 * time.Second, Handler: simple(handleStatsCommand)},
+		{Name: "mystats", Scope: ScopeGroup, Cooldown: 5 * time.Second, Handler: simple(handleMyStatsCommand)},
+		{Name: "admin", Scope: ScopeOwner, Handler: simple(handleAdminCommand)},
+		{Name: "broadcast", Scope: ScopeOwner, Handler: handleBroadcastCommand},
+		{Name: "cancelbroadcast", Scope: ScopeOwner, Handler: handleCancelBroadcastCommand},
+		{Name: "broadcasts", Scope: ScopeOwner, Handler: handleBroadcastsCommand},
+		{Name: "schedule", Scope: ScopeOwner, Handler: handleScheduleCommand},
+		{Name: "scheduled", Scope: ScopeOwner, Handler: handleScheduledCommand},
+		{Name: "unschedule", Scope: ScopeOwner, Handler: handleUnscheduleCommand},
+		{Name: "tag", Scope: ScopeOwner, Handler: handleTagCommand},
+		{Name: "untag", Scope: ScopeOwner, Handler: handleTagCommand},
+	}
+	for _, cmd := range commands {
+		commandByName[cmd.Name] = cmd
+	}
+}
+
+// simple adapts the older (bot, msg) handlers to the registry.
+func simple(h func(*tgbotapi.BotAPI, *tgbotapi.Message)) commandHandler {
+	return func(c *commandContext) { h(c.Bot, c.Msg) }
+}
+
+// dispatchCommand runs msg through the registry. It returns false when
+// msg is not a command we know, so the caller can treat it as a regular
+// message.
+func dispatchCommand(bot *tgbotapi.BotAPI, msg *tgbotapi.Message, threadID int) bool {
+	if !msg.IsCommand() {
+		return false
+	}
+	cmd, ok := commandByName[strings.ToLower(msg.Command())]
+	if !ok {
+		return false
+	}
+	// Owner commands stay secret: to anyone else they are plain messages
+	if cmd.Scope == ScopeOwner && msg.From.ID != ownerID {
+		return false
+	}
+
+	h := cmd.Handler
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		h = middlewares[i](cmd, h)
+	}
+	h(&commandContext{Bot: bot, Msg: msg, ThreadID: threadID, Lang: langFor(msg)})
+	return true
+}
+
+// ─── Middleware ──────────────────────────
+func withRecover(cmd *command, next commandHandler) commandHandler {
+	return func(c *commandContext) {
+		defer func() {
+			if r := recover(); r != nil {
+				logError("panic /"+cmd.Name, c.Bot.Self.UserName, fmt.Errorf("%v", r))
+			}
+		}()
+		next(c)
+	}
+}
+
+func withScope(cmd *command, next commandHandler) commandHandler {
+	return func(c *commandContext) {
+		group := isGroup(c.Msg.Chat)
+		if (cmd.Scope == ScopeGroup && !group) || (cmd.Scope == ScopePrivate && group) {
+			key := "error.group_only"
+			if cmd.Scope == ScopePrivate {
+				key = "error.private_only"
+			}
+			cfg := tgbotapi.NewMessage(c.Msg.Chat.ID, T(c.Lang, key))
+			if _, err := c.Bot.Send(cfg); err != nil {
+				logError(cmd.Name+"ScopeError", c.Bot.Self.UserName, err)
+			}
+			return
+		}
+		next(c)
+	}
+}
+
+func withAdmin(cmd *command, next commandHandler) commandHandler {
+	if !cmd.AdminOnly {
+		return next
+	}
+	return func(c *commandContext) {
+		if !requireAdmin(c.Bot, c.Msg) {
+			return
+		}
+		next(c)
+	}
+}
+
+var (
+	lastUsed      = make(map[cooldownKey]time.Time)
+	cooldownMutex sync.Mutex // protects lastUsed
+)
+
+type cooldownKey struct {
+	chatID int64
+	name   string
+}
+
+// withCooldown drops repeated uses of a command in the same chat without
+// replying, so spamming a command cannot make the bot spam back.
+func withCooldown(cmd *command, next commandHandler) commandHandler {
+	if cmd.Cooldown == 0 {
+		return next
+	}
+	return func(c *commandContext) {
+		key := cooldownKey{chatID: c.Msg.Chat.ID, name: cmd.Name}
+		now := time.Now()
+
+		cooldownMutex.Lock()
+		last, seen := lastUsed[key]
+		if seen && now.Sub(last) < cmd.Cooldown {
+			cooldownMutex.Unlock()
+			log.Printf(ColorYellow+"⏳ /%s on cooldown in chat %d"+ColorReset, cmd.Name, c.Msg.Chat.ID)
+			return
+		}
+		lastUsed[key] = now
+		cooldownMutex.Unlock()
+
+		next(c)
+	}
+}
+
+// ─── Help & Menus ────────────────────────
+// commandMenu is one audience that gets its own command list.
+type commandMenu int
+
+const (
+	menuPrivate    commandMenu = iota // private chats
+	menuGroup                         // regular group members
+	menuGroupAdmin                    // group administrators
+	menuOwner                         // the owner's private chat
+)
+
+// includes derives menu membership from the command definition.
+func (m commandMenu) includes(cmd *command) bool {
+	if cmd.forAdmins() && m == menuGroup {
+		return false
+	}
+	switch cmd.Scope {
+	case ScopePrivate:
+		return m == menuPrivate || m == menuOwner
+	case ScopeGroup:
+		return m == menuGroup || m == menuGroupAdmin
+	case ScopeOwner:
+		return m == menuOwner
+	default:
+		return true
+	}
+}
+
+// menuCommands lists the commands of a menu in registry order.
+func menuCommands(m commandMenu) []*command {
+	var out []*command
+	for _, cmd := range commands {
+		if m.includes(cmd) {
+			out = append(out, cmd)
+		}
+	}
+	return out
+}
+
+// helpMenu picks the menu /help describes. In groups it shows the admin
+// menu, with admin-only commands marked.
+func helpMenu(chat *tgbotapi.Chat, userID int64) commandMenu {
+	switch {
+	case isGroup(chat):
+		return menuGroupAdmin
+	case userID == ownerID:
+		return menuOwner
+	default:
+		return menuPrivate
+	}
+}
+
+func handleHelpCommand(c *commandContext) {
+	var b strings.Builder
+	b.WriteString(T(c.Lang, "help.header"))
+	b.WriteString("\n\n")
+	for _, cmd := range menuCommands(helpMenu(c.Msg.Chat, c.Msg.From.ID)) {
+		fmt.Fprintf(&b, "• /%s - %s", cmd.Name, html.EscapeString(T(c.Lang, "cmd."+cmd.Name)))
+		if cmd.forAdmins() && isGroup(c.Msg.Chat) {
+			b.WriteString(" 👮")
+		}
+		b.WriteString("\n")
+	}
+
+	cfg := tgbotapi.NewMessage(c.Msg.Chat.ID, b.String())
+	cfg.ParseMode = "HTML"
+	if _, err := c.Bot.Send(cfg); err != nil {
+		logError("helpCommand", c.Bot.Self.UserName, err)
+	}
+}
+
+// botCommands builds a menu in one language.
+func botCommands(m commandMenu, lang string) []tgbotapi.BotCommand {
+	var cmds []tgbotapi.BotCommand
+	for _, cmd := range menuCommands(m) {
+		cmds = append(cmds, tgbotapi.BotCommand{Command: cmd.Name, Description: T(lang, "cmd."+cmd.Name)})
+	}
+	return cmds
+}
+
+// registerCommands publishes one menu per audience and locale, so private
+// chats never see group commands and only the owner sees owner commands.
+func registerCommands(bot *tgbotapi.BotAPI) {
+	menus := []struct {
+		scope tgbotapi.BotCommandScope
+		menu  commandMenu
+	}{
+		{tgbotapi.NewBotCommandScopeDefault(), menuPrivate},
+		{tgbotapi.NewBotCommandScopeAllPrivateChats(), menuPrivate},
+		{tgbotapi.NewBotCommandScopeAllGroupChats(), menuGroup},
+		{tgbotapi.NewBotCommandScopeAllChatAdministrators(), menuGroupAdmin},
+		{tgbotapi.NewBotCommandScopeChat(ownerID), menuOwner},
+	}
+
+	for _, m := range menus {
+		for _, lang := range availableLangs() {
+			code := lang
+			if lang == defaultLang {
+				code = ""
+			}
+			cfg := tgbotapi.NewSetMyCommandsWithScopeAndLanguage(m.scope, code, botCommands(m.menu, lang)...)
+			if _, err := bot.Request(cfg); err != nil {
+				// The owner scope fails until the owner has started this bot
+				logError("setMyCommands "+m.scope.Type+" "+lang, bot.Self.UserName, err)
+			}
+		}
+	}
+	log.Printf(ColorGreen+"📋 Command menus published for @%s"+ColorReset, bot.Self.UserName)
+}
+
+// ─── Core Commands ───────────────────────
+// handleStartCommand shows the group welcome in groups and the private
+// welcome elsewhere. A group /start may carry a preset from the
+// startgroup link.
+func handleStartCommand(c *commandContext) {
+	if isGroup(c.Msg.Chat) {
+		// A startgroup link also triggers my_chat_member, which already greeted
+		if c.Msg.CommandArguments() == "" || !recentlyWelcomed(c.Msg.Chat.ID) {
+			sendGroupWelcome(c.Bot, c.Msg)
+		}
+		applyStartPreset(c.Bot, c.Msg)
+		return
+	}
+	go reactToMessage(c.Bot, c.Msg)
+	sendWelcome(c.Bot, c.Msg)
+}
+
+func handleBeginCommand(c *commandContext) {
+	msg, lang := c.Msg, c.Lang
+	scope := applyReactionToggle(msg, c.ThreadID, true)
+
+	// React to the command first
+	go reactToMessage(c.Bot, msg)
+
+	// Send confirmation with random image
+	kb := tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonURL(T(lang, "btn.updates"), channelURL),
+			tgbotapi.NewInlineKeyboardButtonURL(T(lang, "btn.support"), groupURL),
+		),
+	)
+
+	message, mode := renderTemplate("begin", lang, templateData{
+		Chat: msg.Chat, User: msg.From, Bot: c.Bot,
+		Extra: map[string]string{"scope": T(lang, "scope."+scope)},
+	})
+
+	photo := tgbotapi.NewPhoto(msg.Chat.ID, nil)
+	photo.Caption = message
+	photo.ParseMode = mode
+	photo.ReplyMarkup = kb
+	// Replying keeps the answer inside the forum topic
+	if c.ThreadID != 0 {
+		photo.ReplyToMessageID = msg.MessageID
+	}
+
+	if _, err := sendLibraryPhoto(c.Bot, photo); err != nil {
+		log.Printf(ColorRed+"❌ Failed to send begin photo, falling back to text: %v"+ColorReset, err)
+		cfg := tgbotapi.NewMessage(msg.Chat.ID, message)
+		cfg.ParseMode = mode
+		cfg.ReplyMarkup = kb
+		cfg.ReplyToMessageID = photo.ReplyToMessageID
+		if _, err := c.Bot.Send(cfg); err != nil {
+			logError("beginFallback", c.Bot.Self.UserName, err)
+		}
+	}
+
+	log.Printf(ColorGreen+"✅ Reactions enabled for %s %d/%d"+ColorReset, scope, msg.Chat.ID, c.ThreadID)
+}
+
+func handleEndCommand(c *commandContext) {
+	msg, lang := c.Msg, c.Lang
+	scope := applyReactionToggle(msg, c.ThreadID, false)
+
+	message, mode := renderTemplate("end", lang, templateData{
+		Chat: msg.Chat, User: msg.From, Bot: c.Bot,
+		Extra: map[string]string{"scope": T(lang, "scope."+scope)},
+	})
+	cfg := tgbotapi.NewMessage(msg.Chat.ID, message)
+	cfg.ParseMode = mode
+	if c.ThreadID != 0 {
+		cfg.ReplyToMessageID = msg.MessageID
+	}
+
+	if _, err := c.Bot.Send(cfg); err != nil {
+		logError("endCommand", c.Bot.Self.UserName, err)
+	}
+
+	log.Printf(ColorYellow+"🛑 Reactions disabled for %s %d/%d"+ColorReset, scope, msg.Chat.ID, c.ThreadID)
+}
+
+// handlePingCommand reacts first, then measures how long one send takes.
+func handlePingCommand(c *commandContext) {
+	msg := c.Msg
+	// Only react if reactions are enabled or in private chat
+	if !isGroup(msg.Chat) || areTopicReactionsEnabled(msg.Chat.ID, c.ThreadID) {
+		go reactToMessage(c.Bot, msg)
+	}
+
+	log.Println(ColorBlue + "🏓 /ping command received" + ColorReset)
+	start := time.Now()
+
+	// In groups reply to the original message, in private just answer
+	cfg := tgbotapi.NewMessage(msg.Chat.ID, "🛰️ Pinging...")
+	if isGroup(msg.Chat) {
+		cfg.ReplyToMessageID = msg.MessageID
+	}
+
+	sentMsg, err := c.Bot.Send(cfg)
+	if err != nil {
+		logError("pingSend", c.Bot.Self.UserName, err)
+		return
+	}
+
+	elapsed := float64(time.Since(start).Microseconds()) / 1000 // ms
+	latency := fmt.Sprintf("%.2fms", elapsed)
+
+	text, mode := renderTemplate("ping", c.Lang, templateData{
+		Chat: msg.Chat, User: msg.From, Bot: c.Bot,
+		Extra: map[string]string{"latency": latency},
+	})
+	edit := tgbotapi.NewEditMessageText(msg.Chat.ID, sentMsg.MessageID, text)
+	edit.ParseMode = mode
+	edit.DisableWebPagePreview = true
+
+	if _, err := c.Bot.Send(edit); err != nil {
+		logError("pingEdit", c.Bot.Self.UserName, err)
+	} else {
+		log.Printf(ColorGreen+"⚡ Ping responded in %s"+ColorReset, latency)
+	}
+}
@@ -0,0 +1,296 @@
+package main
+
+import (
+	"fmt"
+	"html"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// ─── Recent Errors ───────────────────────
+const maxRecentErrors = 20
+
+type errorEntry struct {
+	At      time.Time
+	Scope   string
+	Context string
+	Err     string
+}
+
+var (
+	recentErrors []errorEntry // newest last, at most maxRecentErrors
+	errorMutex   sync.Mutex   // protects recentErrors
+)
+
+// rememberError keeps err for the dashboard, without any bot token a
+// request error may quote.
+func rememberError(scope, context string, err error) {
+	errorMutex.Lock()
+	defer errorMutex.Unlock()
+	recentErrors = append(recentErrors, errorEntry{
+		At:      time.Now(),
+		Scope:   scope,
+		Context: redactToken(context),
+		Err:     redactToken(err.Error()),
+	})
+	if len(recentErrors) > maxRecentErrors {
+		recentErrors = recentErrors[len(recentErrors)-maxRecentErrors:]
+	}
+}
+
+func lastErrors(n int) []errorEntry {
+	errorMutex.Lock()
+	defer errorMutex.Unlock()
+	if n > len(recentErrors) {
+		n = len(recentErrors)
+	}
+	out := make([]errorEntry, n)
+	copy(out, recentErrors[len(recentErrors)-n:])
+	return out
+}
+
+// ─── Owner Dashboard ─────────────────────
+// Button data is "adm:<page>". The dashboard lists every chat and bot,
+// so it only opens in the owner's private chat.
+const adminPrefix = "adm:"
+
+func handleAdminCommand(bot *tgbotapi.BotAPI, msg *tgbotapi.Message) {
+	if !msg.Chat.IsPrivate() {
+		cfg := tgbotapi.NewMessage(msg.Chat.ID, T(langFor(msg), "error.private_only"))
+		if _, err := bot.Send(cfg); err != nil {
+			logError("adminDashboard", bot.Self.UserName, err)
+		}
+		return
+	}
+	log.Printf(ColorBlue+"🛠️  /admin by owner via bot %s"+ColorReset, bot.Self.UserName)
+
+	cfg := tgbotapi.NewMessage(msg.Chat.ID, dashboardPage("main"))
+	cfg.ParseMode = "HTML"
+	cfg.ReplyMarkup = dashboardKeyboard()
+	if _, err := bot.Send(cfg); err != nil {
+		logError("adminDashboard", bot.Self.UserName, err)
+	}
+}
+
+func handleAdminCallback(bot *tgbotapi.BotAPI, cb *tgbotapi.CallbackQuery) {
+	if cb.From.ID != ownerID || !cb.Message.Chat.IsPrivate() {
+		answerCallback(bot, cb.ID, T(langFor(&tgbotapi.Message{Chat: cb.Message.Chat, From: cb.From}), "error.owner_only"))
+		return
+	}
+	page := strings.TrimPrefix(cb.Data, adminPrefix)
+	answerCallback(bot, cb.ID, "")
+
+	edit := tgbotapi.NewEditMessageTextAndMarkup(cb.Message.Chat.ID, cb.Message.MessageID, dashboardPage(page), dashboardKeyboard())
+	edit.ParseMode = "HTML"
+	if _, err := bot.Send(edit); err != nil {
+		logError("adminEdit", bot.Self.UserName, err)
+	}
+}
+
+func dashboardKeyboard() tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🤖 Bots", adminPrefix+"bots"),
+			tgbotapi.NewInlineKeyboardButtonData("👥 Subscribers", adminPrefix+"subs"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("💬 Groups", adminPrefix+"groups"),
+			tgbotapi.NewInlineKeyboardButtonData("❌ Errors", adminPrefix+"errors"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🔄 Overview", adminPrefix+"main"),
+		),
+	)
+}
+
+func dashboardPage(page string) string {
+	switch page {
+	case "bots":
+		return dashboardBots()
+	case "subs":
+		return dashboardSubscribers()
+	case "groups":
+		return dashboardGroups()
+	case "errors":
+		return dashboardErrors()
+	default:
+		return dashboardOverview()
+	}
+}
+
+// reactionsLastDay counts reactions per bot ID over the last 24 hours.
+func reactionsLastDay() map[int64]int {
+	counts := make(map[int64]int)
+	rows, err := db.Query(`SELECT COALESCE(bot_id, 0), COUNT(*) FROM reactions
+		WHERE timestamp >= datetime('now', '-1 day') GROUP BY bot_id`)
+	if err != nil {
+		logError("reactionsLastDay", "dashboard", err)
+		return counts
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var botID int64
+		var n int
+		if err := rows.Scan(&botID, &n); err != nil {
+			logError("reactionsLastDay scan", "dashboard", err)
+			return counts
+		}
+		counts[botID] = n
+	}
+	return counts
+}
+
+// subscribersByType counts subscribers per chat type.
+func subscribersByType() map[string]int {
+	subMutex.RLock()
+	defer subMutex.RUnlock()
+	counts := make(map[string]int)
+	for _, sub := range subscribers {
+		if !sub.Inactive {
+			counts[sub.Type]++
+		}
+	}
+	return counts
+}
+
+// groupReactionCounts splits known groups into enabled and disabled.
+func groupReactionCounts() (enabled, disabled int) {
+	subMutex.RLock()
+	var groups []int64
+	for id, sub := range subscribers {
+		if !sub.Inactive && (sub.Type == "group" || sub.Type == "supergroup") {
+			groups = append(groups, id)
+		}
+	}
+	subMutex.RUnlock()
+
+	for _, id := range groups {
+		if areReactionsEnabled(id) {
+			enabled++
+		} else {
+			disabled++
+		}
+	}
+	return enabled, disabled
+}
+
+func dashboardOverview() string {
+	botMutex.RLock()
+	botCount := len(botInstances)
+	botMutex.RUnlock()
+
+	total := 0
+	for _, n := range reactionsLastDay() {
+		total += n
+	}
+	byType := subscribersByType()
+	enabled, disabled := groupReactionCounts()
+
+	return fmt.Sprintf("🛠️ <b>Admin Dashboard</b>\n\n"+
+		"🤖 Bots: <b>%d</b>\n"+
+		"👥 Subscribers: <b>%d</b> private, <b>%d</b> groups, <b>%d</b> supergroups, <b>%d</b> channels\n"+
+		"💬 Groups: <b>%d</b> reacting, <b>%d</b> stopped\n"+
+		"💖 Reactions (24h): <b>%d</b>\n"+
+		"❌ Recent errors: <b>%d</b>\n\n"+
+		"<i>Updated %s UTC</i>",
+		botCount, byType["private"], byType["group"], byType["supergroup"], byType["channel"],
+		enabled, disabled, total, len(lastErrors(maxRecentErrors)), time.Now().UTC().Format("15:04:05"))
+}
+
+func dashboardBots() string {
+	counts := reactionsLastDay()
+
+	botMutex.RLock()
+	bots := append([]*tgbotapi.BotAPI(nil), botInstances...)
+	botMutex.RUnlock()
+
+	var b strings.Builder
+	b.WriteString("🤖 <b>Bots</b>\n\n")
+	for _, bot := range bots {
+		mutex.Lock()
+		fails := failCount[bot.Self.UserName]
+		mutex.Unlock()
+		fmt.Fprintf(&b, "• @%s [<code>%d</code>]\n   💖 %d reactions (24h), ⚠️ %d failures\n",
+			html.EscapeString(bot.Self.UserName), bot.Self.ID, counts[bot.Self.ID], fails)
+	}
+	if n := counts[0]; n > 0 {
+		fmt.Fprintf(&b, "\n<i>%d reactions logged before bot tracking</i>", n)
+	}
+	return b.String()
+}
+
+func dashboardSubscribers() string {
+	byType := subscribersByType()
+	types := make([]string, 0, len(byType))
+	total := 0
+	for t, n := range byType {
+		types = append(types, t)
+		total += n
+	}
+	sort.Strings(types)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "👥 <b>Subscribers</b> (%d)\n\n", total)
+	for _, t := range types {
+		fmt.Fprintf(&b, "• %s: <b>%d</b>\n", html.EscapeString(t), byType[t])
+	}
+	return b.String()
+}
+
+func dashboardGroups() string {
+	const limit = 20
+	type group struct {
+		id      int64
+		title   string
+		enabled bool
+	}
+
+	subMutex.RLock()
+	var groups []group
+	for id, sub := range subscribers {
+		if !sub.Inactive && (sub.Type == "group" || sub.Type == "supergroup") {
+			groups = append(groups, group{id: id, title: sub.Title})
+		}
+	}
+	subMutex.RUnlock()
+	for i := range groups {
+		groups[i].enabled = areReactionsEnabled(groups[i].id)
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].title < groups[j].title })
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "💬 <b>Groups</b> (%d)\n\n", len(groups))
+	for i, g := range groups {
+		if i == limit {
+			fmt.Fprintf(&b, "\n<i>…and %d more</i>", len(groups)-limit)
+			break
+		}
+		state := "✅"
+		if !g.enabled {
+			state = "⏸️"
+		}
+		fmt.Fprintf(&b, "%s %s <code>%d</code>\n", state, html.EscapeString(g.title), g.id)
+	}
+	return b.String()
+}
+
+func dashboardErrors() string {
+	errs := lastErrors(10)
+	if len(errs) == 0 {
+		return "❌ <b>Recent Errors</b>\n\nNo errors since startup 🎉"
+	}
+
+	var b strings.Builder
+	b.WriteString("❌ <b>Recent Errors</b>\n\n")
+	for i := len(errs) - 1; i >= 0; i-- {
+		e := errs[i]
+		fmt.Fprintf(&b, "<code>%s</code> [%s/%s]\n%s\n\n", e.At.UTC().Format("01-02 15:04:05"),
+			html.EscapeString(e.Scope), html.EscapeString(e.Context), html.EscapeString(e.Err))
+	}
+	return b.String()
+}
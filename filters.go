@@ -0,0 +1,172 @@
+package main
+
+import (
+	"log"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// ─── Message Filters ─────────────────────
+// messageFilters is the per-chat configuration of the filter chain that
+// runs before a regular message gets a reaction.
+type messageFilters struct {
+	SkipBots      bool
+	SkipForwards  bool
+	SkipService   bool
+	MinTextLength int // in runes, 0 disables the check
+	MediaOnly     bool
+}
+
+var (
+	defaultFilters = messageFilters{SkipBots: true, SkipService: true}
+	chatFilters    = make(map[int64]messageFilters) // chatID -> filter config, guarded by reactionMutex
+)
+
+// messageFilter is one link of the chain. skip returns true when msg
+// should not get a reaction under the given config.
+type messageFilter struct {
+	name string
+	skip func(f messageFilters, msg *tgbotapi.Message) bool
+}
+
+var filterChain = []messageFilter{
+	{"bots", func(f messageFilters, msg *tgbotapi.Message) bool {
+		// Anonymous admins and channel posts come from service bots
+		// with SenderChat set; those are people, not bots
+		return f.SkipBots && ((msg.From.IsBot && msg.SenderChat == nil) || msg.ViaBot != nil)
+	}},
+	{"forwards", func(f messageFilters, msg *tgbotapi.Message) bool {
+		return f.SkipForwards && isForward(msg)
+	}},
+	{"service", func(f messageFilters, msg *tgbotapi.Message) bool {
+		return f.SkipService && isServiceMessage(msg)
+	}},
+	{"media", func(f messageFilters, msg *tgbotapi.Message) bool {
+		return f.MediaOnly && !hasMedia(msg)
+	}},
+	{"minlen", func(f messageFilters, msg *tgbotapi.Message) bool {
+		if f.MinTextLength == 0 || hasMedia(msg) {
+			return false
+		}
+		return utf8.RuneCountInString(msg.Text) < f.MinTextLength
+	}},
+}
+
+func getChatFilters(chatID int64) messageFilters {
+	reactionMutex.RLock()
+	defer reactionMutex.RUnlock()
+	if f, ok := chatFilters[chatID]; ok {
+		return f
+	}
+	return defaultFilters
+}
+
+func setChatFilters(chatID int64, f messageFilters) {
+	reactionMutex.Lock()
+	defer reactionMutex.Unlock()
+	chatFilters[chatID] = f
+	log.Printf(ColorBlue+"🧹 Filters for chat %d: %+v"+ColorReset, chatID, f)
+}
+
+// filteredBy runs the chain and returns the name of the first filter that
+// rejects msg, or "" when the message may get a reaction.
+func filteredBy(msg *tgbotapi.Message) string {
+	f := getChatFilters(msg.Chat.ID)
+	for _, filter := range filterChain {
+		if filter.skip(f, msg) {
+			return filter.name
+		}
+	}
+	return ""
+}
+
+func isForward(msg *tgbotapi.Message) bool {
+	return msg.ForwardDate != 0 || msg.ForwardFrom != nil || msg.ForwardFromChat != nil || msg.ForwardSenderName != ""
+}
+
+func isServiceMessage(msg *tgbotapi.Message) bool {
+	return len(msg.NewChatMembers) > 0 || msg.LeftChatMember != nil ||
+		msg.NewChatTitle != "" || len(msg.NewChatPhoto) > 0 || msg.DeleteChatPhoto ||
+		msg.GroupChatCreated || msg.SuperGroupChatCreated || msg.ChannelChatCreated ||
+		msg.MessageAutoDeleteTimerChanged != nil || msg.MigrateToChatID != 0 || msg.MigrateFromChatID != 0 ||
+		msg.PinnedMessage != nil || msg.ProximityAlertTriggered != nil ||
+		msg.VoiceChatScheduled != nil || msg.VoiceChatStarted != nil || msg.VoiceChatEnded != nil ||
+		msg.VoiceChatParticipantsInvited != nil
+}
+
+func hasMedia(msg *tgbotapi.Message) bool {
+	return len(msg.Photo) > 0 || msg.Video != nil || msg.Animation != nil || msg.Document != nil ||
+		msg.Sticker != nil || msg.Audio != nil || msg.Voice != nil || msg.VideoNote != nil
+}
+
+// ─── /filter Command ─────────────────────
+func handleFilterCommand(bot *tgbotapi.BotAPI, msg *tgbotapi.Message) {
+	args := strings.Fields(strings.ToLower(msg.CommandArguments()))
+	f := getChatFilters(msg.Chat.ID)
+	lang := langFor(msg)
+
+	if len(args) == 0 {
+		sendFilterText(bot, msg.Chat.ID, describeFilters(lang, f))
+		return
+	}
+	if !requireAdmin(bot, msg) {
+		return
+	}
+	if len(args) != 2 {
+		sendFilterText(bot, msg.Chat.ID, T(lang, "filter.usage"))
+		return
+	}
+
+	name, value := args[0], args[1]
+	if name == "minlen" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			sendFilterText(bot, msg.Chat.ID, T(lang, "filter.bad_minlen"))
+			return
+		}
+		f.MinTextLength = n
+	} else {
+		if value != "on" && value != "off" {
+			sendFilterText(bot, msg.Chat.ID, T(lang, "filter.bad_value"))
+			return
+		}
+		on := value == "on"
+		switch name {
+		case "bots":
+			f.SkipBots = on
+		case "forwards":
+			f.SkipForwards = on
+		case "service":
+			f.SkipService = on
+		case "media":
+			f.MediaOnly = on
+		default:
+			sendFilterText(bot, msg.Chat.ID, T(lang, "filter.unknown"))
+			return
+		}
+	}
+
+	setChatFilters(msg.Chat.ID, f)
+	sendFilterText(bot, msg.Chat.ID, T(lang, "filter.updated")+"\n\n"+describeFilters(lang, f))
+}
+
+func describeFilters(lang string, f messageFilters) string {
+	onOff := map[bool]string{true: "✅", false: "❌"}
+	minLen := T(lang, "filter.off")
+	if f.MinTextLength > 0 {
+		minLen = strconv.Itoa(f.MinTextLength)
+	}
+	return T(lang, "filter.panel",
+		onOff[f.SkipBots], onOff[f.SkipForwards], onOff[f.SkipService], onOff[f.MediaOnly], minLen)
+}
+
+func sendFilterText(bot *tgbotapi.BotAPI, chatID int64, text string) {
+	cfg := tgbotapi.NewMessage(chatID, text)
+	cfg.ParseMode = "HTML"
+	if _, err := bot.Send(cfg); err != nil {
+		logError("filterCommand", bot.Self.UserName, err)
+	}
+}
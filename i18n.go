@@ -0,0 +1,524 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// ─── Message Catalog ─────────────────────
+const defaultLang = "en"
+
+// catalog maps language code -> message key -> text. Every key must exist
+// in defaultLang; other locales fall back to it for missing keys. Longer
+// texts live in templates instead.
+var catalog = map[string]map[string]string{
+	"en": {
+		"lang.name": "🇬🇧 English",
+
+		"btn.updates":   "Updates",
+		"btn.support":   "Support",
+		"btn.add_group": "Add Me To Your Group",
+		"btn.preset":    "🎨 Add With a Preset",
+		"btn.back":      "⬅️ Back",
+
+		"scope.group": "group",
+		"scope.topic": "topic",
+
+		"error.group_only":   "❌ This command only works in groups!",
+		"error.admin_only":   "🚫 Only group admins can use this command!",
+		"error.private_only": "❌ This command only works in private chat!",
+		"error.owner_only":   "🚫 Only the bot owner can do this!",
+
+		"broadcast.activated":     "🚀 *Broadcast Mode Activated!* 🚀\n\nSend any content now. I'll show you a preview, and it goes out once to every subscriber when you confirm.\n\nTo cancel, send /cancelbroadcast",
+		"broadcast.deactivated":   "🛑 *Broadcast Mode Deactivated.*",
+		"broadcast.summary":       "📊 *Broadcast Complete!*\n\n✅ Successful: %d\n❌ Failed: %d\n🤖 Total Bots: %d\n👥 Total Subscribers: %d",
+		"broadcast.audience":      "🎯 Audience: `%s`\n👥 %d of %d subscribers match",
+		"broadcast.bad_audience":  "❌ %v\n\nFilters: type= lang= active= reactions= tag= bot=",
+		"broadcast.progress":      "📢 Broadcasting...\n\n✅ Sent: %d\n❌ Failed: %d\n⏳ Remaining: %d\n🕒 ETA: %s",
+		"broadcast.preview":       "👀 *Preview*\n\n🎯 Audience: `%s`\n👥 %d chats\n\nCheck the message below, then choose how to send it.",
+		"broadcast.btn_send":      "🚀 Send",
+		"broadcast.btn_silent":    "🔕 Send silently",
+		"broadcast.btn_pin":       "📌 Pin after sending: %s",
+		"broadcast.btn_cancel":    "✖️ Cancel",
+		"broadcast.pin_on":        "on",
+		"broadcast.pin_off":       "off",
+		"broadcast.starting":      "🚀 Sending…",
+		"broadcast.cancelled":     "🛑 Broadcast cancelled",
+		"broadcast.expired":       "⌛ This preview has expired",
+		"broadcast.history":       "🗃️ <b>Broadcast history</b>",
+		"broadcast.history_entry": "%s <b>#%d</b> · %s UTC\n   %s\n   ✅ %d sent, ❌ %d failed, ⏳ %d left",
+		"broadcast.history_empty": "<i>No broadcasts yet.</i>",
+		"tag.usage":               "❌ Usage: /%s <chat_id> <tag>...",
+		"tag.bad_chat":            "❌ Bad chat ID: %s",
+		"tag.updated":             "✅ Tags of %d updated: %s",
+		"tag.error":               "❌ Could not load tags",
+		"tag.title":               "🏷️ Tags",
+		"tag.entry":               "• %s: %d chats",
+		"tag.empty":               "No tags yet. Add one with /tag <chat_id> <tag>",
+
+		"mode.current":     "🎛️ Current reaction mode: <b>%s</b>\n\n• /mode normal - React as soon as a message arrives\n• /mode mirror - Copy the first reaction a member adds",
+		"mode.set":         "✅ Reaction mode set to <b>%s</b>!",
+		"mode.mirror_hint": "\n\nI'll copy the first reaction on each message, or pick my own after %s.",
+		"mode.need_admin":  "\n\n<i>I need to be an admin to see reactions in groups 😉</i>",
+		"mode.unknown":     "❌ Unknown mode! Use /mode normal or /mode mirror.",
+
+		"filter.panel":      "🧹 <b>Message Filters</b>\n\n%s Skip bots\n%s Skip forwards\n%s Skip service messages\n%s Media only\n📏 Minimum text length: %s\n\n<i>Change with /filter bots off, /filter minlen 5, ...</i>",
+		"filter.off":        "off",
+		"filter.usage":      "❌ Usage: /filter &lt;bots|forwards|service|media&gt; &lt;on|off&gt; or /filter minlen &lt;number&gt;",
+		"filter.bad_minlen": "❌ Minimum length must be a number like 3, or 0 to disable.",
+		"filter.bad_value":  "❌ Use <code>on</code> or <code>off</code>.",
+		"filter.unknown":    "❌ Unknown filter! Try bots, forwards, service, media or minlen.",
+		"filter.updated":    "✅ Filters updated!",
+
+		"everyone.on":       "🔓 Everyone can now use /begin, /end and other settings commands.",
+		"everyone.off":      "🔒 Only admins can use /begin, /end and other settings commands now.",
+		"everyone.admins":   "admins only",
+		"everyone.everyone": "everyone",
+		"everyone.status":   "👮 Settings commands are currently open to: <b>%s</b>\n\n• /everyone on - Let every member change settings\n• /everyone off - Restrict settings to admins",
+
+		"settings.panel":          "⚙️ <b>Reaction Settings</b>\n\n💖 Reactions: <b>%s</b>\n🎨 Palette: <b>%s</b>\n🎲 Rate: <b>%d%%</b>\n🌙 Quiet hours: <b>%s</b>\n🪞 Mode: <b>%s</b>\n\n<i>Tap a button to change a setting.</i>",
+		"settings.on":             "ON ✅",
+		"settings.off":            "OFF ❌",
+		"settings.in_topic":       " (this topic)",
+		"settings.quiet_off":      "off",
+		"settings.btn_on":         "💖 Reactions: ON",
+		"settings.btn_off":        "💤 Reactions: OFF",
+		"settings.btn_palette":    "🎨 Palette: %s",
+		"settings.btn_rate":       "🎲 Rate: %d%%",
+		"settings.btn_quiet":      "🌙 Quiet: %s",
+		"settings.btn_mode":       "🪞 Mode: %s",
+		"settings.btn_close":      "✖️ Close",
+		"settings.choose_palette": "🎨 <b>Choose a palette</b>\n\n<i>I'll only react with emojis from it.</i>",
+		"settings.admins_only":    "🚫 Only group admins can change settings!",
+		"settings.saved":          "✅ Saved",
+		"settings.unknown":        "❓ Unknown button",
+
+		"stats.error":        "❌ Couldn't load stats right now, try again later!",
+		"stats.empty":        "📊 <b>Reaction Stats</b>\n\nNo reactions yet! Send some messages and I'll get to work ✨",
+		"stats.title":        "📊 <b>Reaction Stats for %s</b>",
+		"stats.total":        "💖 Total reactions: <b>%d</b>",
+		"stats.top_emojis":   "🏆 <b>Top Emojis</b>",
+		"stats.last_week":    "📅 <b>Last 7 Days</b>",
+		"stats.top_messages": "🔥 <b>Most Reacted Messages</b>",
+		"stats.message":      "Message #%d",
+		"mystats.error":      "❌ Couldn't load your stats right now, try again later!",
+		"mystats.empty":      "📊 I haven't reacted to any of your messages here yet. Keep chatting! ✨",
+		"mystats.body":       "📊 <b>Stats for %s</b>\n\n💖 Reactions received: <b>%d</b>\n😍 Favorite emoji: %s\n🏆 Rank: <b>#%d</b> of %d",
+
+		"schedule.not_armed": "❌ Start with /broadcast, then /schedule <when>.",
+		"schedule.usage":     "🗓️ Usage: /schedule in 2h | 18:30 | 2026-12-24 18:00 | daily 09:00 | weekly mon 09:00 | every 6h",
+		"schedule.set":       "🗓️ Next message will be scheduled (%s), first run %s UTC.\nSend it now, or /cancelbroadcast.",
+		"schedule.queued":    "✅ Scheduled #%d (%s), first run %s UTC. See /scheduled.",
+		"schedule.failed":    "❌ Could not schedule the broadcast.",
+		"schedule.passed":    "❌ %s has already passed, nothing was scheduled. Start again with /broadcast.",
+		"scheduled.title":    "🗓️ <b>Scheduled broadcasts</b>",
+		"scheduled.item":     "<b>#%d</b> · next %s UTC · %s\n   🎯 %s\n   %s",
+		"scheduled.empty":    "<i>Nothing scheduled.</i>",
+		"scheduled.hint":     "Cancel one with /unschedule &lt;id&gt;",
+		"unschedule.usage":   "❌ Usage: /unschedule <id>",
+		"unschedule.missing": "❌ No active schedule #%d.",
+		"unschedule.done":    "🛑 Schedule #%d cancelled.",
+
+		"help.header": "📋 <b>Commands</b>",
+
+		"preset.applied":         "🎁 Preset applied: %s",
+		"preset.unknown_palette": "❓ Unknown palette",
+
+		"member.need_admin": "👀 I can only see commands here. Make me an admin so I can react to every message!",
+		"member.promoted":   "✅ Thanks for the admin rights, I can react to every message now!",
+
+		"lang.current": "🌐 Current language: %s\n\nChoose another one with /lang followed by a code:\n%s",
+		"lang.set":     "✅ Language set to %s!",
+		"lang.unknown": "❌ I don't speak that one yet! Available: %s",
+
+		"cmd.start":           "Show welcome message",
+		"cmd.begin":           "Start reactions in group",
+		"cmd.end":             "Stop reactions in group",
+		"cmd.ping":            "Check my response time",
+		"cmd.status":          "Show detailed health diagnostics",
+		"cmd.settings":        "Open the settings panel",
+		"cmd.lang":            "Change my language",
+		"cmd.help":            "List available commands",
+		"cmd.mode":            "Switch between instant and mirror reactions",
+		"cmd.filter":          "Choose which messages get reactions",
+		"cmd.everyone":        "Let all members change settings",
+		"cmd.stats":           "Show this group's reaction stats",
+		"cmd.mystats":         "Show the reactions you received",
+		"cmd.admin":           "Owner dashboard",
+		"cmd.broadcast":       "Broadcast the next message",
+		"cmd.cancelbroadcast": "Cancel broadcast mode",
+		"cmd.broadcasts":      "Show broadcast history",
+		"cmd.schedule":        "Schedule the next broadcast",
+		"cmd.scheduled":       "List scheduled broadcasts",
+		"cmd.unschedule":      "Cancel a scheduled broadcast",
+		"cmd.tag":             "Tag a chat for targeted broadcasts",
+		"cmd.untag":           "Remove a chat tag",
+	},
+	"es": {
+		"lang.name": "🇪🇸 Español",
+
+		"btn.updates":   "Novedades",
+		"btn.support":   "Soporte",
+		"btn.add_group": "Añádeme a tu grupo",
+		"btn.preset":    "🎨 Añadir con un preset",
+		"btn.back":      "⬅️ Atrás",
+
+		"scope.group": "grupo",
+		"scope.topic": "tema",
+
+		"error.group_only":   "❌ ¡Este comando solo funciona en grupos!",
+		"error.admin_only":   "🚫 ¡Solo los administradores pueden usar este comando!",
+		"error.private_only": "❌ ¡Este comando solo funciona en chat privado!",
+		"error.owner_only":   "🚫 ¡Solo el dueño del bot puede hacer esto!",
+
+		"broadcast.activated":     "🚀 *¡Modo difusión activado!* 🚀\n\nEnvía cualquier contenido. Te mostraré una vista previa y, al confirmar, se entregará una vez a cada suscriptor.\n\nPara cancelar, envía /cancelbroadcast",
+		"broadcast.deactivated":   "🛑 *Modo difusión desactivado.*",
+		"broadcast.summary":       "📊 *¡Difusión completada!*\n\n✅ Enviados: %d\n❌ Fallidos: %d\n🤖 Bots: %d\n👥 Suscriptores: %d",
+		"broadcast.audience":      "🎯 Audiencia: `%s`\n👥 %d de %d suscriptores coinciden",
+		"broadcast.progress":      "📢 Difundiendo...\n\n✅ Enviados: %d\n❌ Fallidos: %d\n⏳ Pendientes: %d\n🕒 Tiempo restante: %s",
+		"broadcast.preview":       "👀 *Vista previa*\n\n🎯 Audiencia: `%s`\n👥 %d chats\n\nRevisa el mensaje de abajo y elige cómo enviarlo.",
+		"broadcast.btn_send":      "🚀 Enviar",
+		"broadcast.btn_silent":    "🔕 Enviar en silencio",
+		"broadcast.btn_pin":       "📌 Fijar al enviar: %s",
+		"broadcast.btn_cancel":    "✖️ Cancelar",
+		"broadcast.pin_on":        "sí",
+		"broadcast.pin_off":       "no",
+		"broadcast.starting":      "🚀 Enviando…",
+		"broadcast.cancelled":     "🛑 Difusión cancelada",
+		"broadcast.expired":       "⌛ Esta vista previa ha caducado",
+		"broadcast.history":       "🗃️ <b>Historial de difusiones</b>",
+		"broadcast.history_entry": "%s <b>#%d</b> · %s UTC\n   %s\n   ✅ %d enviados, ❌ %d fallidos, ⏳ %d pendientes",
+		"broadcast.history_empty": "<i>Aún no hay difusiones.</i>",
+		"tag.usage":               "❌ Uso: /%s <chat_id> <etiqueta>...",
+		"tag.bad_chat":            "❌ ID de chat no válido: %s",
+		"tag.updated":             "✅ Etiquetas de %d actualizadas: %s",
+		"tag.error":               "❌ No se pudieron cargar las etiquetas",
+		"tag.title":               "🏷️ Etiquetas",
+		"tag.entry":               "• %s: %d chats",
+		"tag.empty":               "Aún no hay etiquetas. Añade una con /tag <chat_id> <etiqueta>",
+
+		"preset.applied":         "🎁 Preset aplicado: %s",
+		"preset.unknown_palette": "❓ Paleta desconocida",
+
+		"mode.current":     "🎛️ Modo de reacción actual: <b>%s</b>\n\n• /mode normal - Reaccionar en cuanto llega un mensaje\n• /mode mirror - Copiar la primera reacción de un miembro",
+		"mode.set":         "✅ ¡Modo de reacción cambiado a <b>%s</b>!",
+		"mode.mirror_hint": "\n\nCopiaré la primera reacción de cada mensaje, o elegiré la mía tras %s.",
+		"mode.need_admin":  "\n\n<i>Necesito ser administrador para ver las reacciones en grupos 😉</i>",
+		"mode.unknown":     "❌ ¡Modo desconocido! Usa /mode normal o /mode mirror.",
+
+		"filter.panel":      "🧹 <b>Filtros de mensajes</b>\n\n%s Ignorar bots\n%s Ignorar reenvíos\n%s Ignorar mensajes de servicio\n%s Solo multimedia\n📏 Longitud mínima del texto: %s\n\n<i>Cámbialos con /filter bots off, /filter minlen 5, ...</i>",
+		"filter.off":        "no",
+		"filter.usage":      "❌ Uso: /filter &lt;bots|forwards|service|media&gt; &lt;on|off&gt; o /filter minlen &lt;número&gt;",
+		"filter.bad_minlen": "❌ La longitud mínima debe ser un número como 3, o 0 para desactivarla.",
+		"filter.bad_value":  "❌ Usa <code>on</code> u <code>off</code>.",
+		"filter.unknown":    "❌ ¡Filtro desconocido! Prueba bots, forwards, service, media o minlen.",
+		"filter.updated":    "✅ ¡Filtros actualizados!",
+
+		"everyone.on":       "🔓 Ahora todos pueden usar /begin, /end y los demás comandos de ajustes.",
+		"everyone.off":      "🔒 Ahora solo los administradores pueden usar /begin, /end y los demás comandos de ajustes.",
+		"everyone.admins":   "solo administradores",
+		"everyone.everyone": "todos",
+		"everyone.status":   "👮 Los comandos de ajustes están abiertos a: <b>%s</b>\n\n• /everyone on - Todos los miembros pueden cambiar ajustes\n• /everyone off - Solo los administradores",
+
+		"settings.panel":          "⚙️ <b>Ajustes de reacciones</b>\n\n💖 Reacciones: <b>%s</b>\n🎨 Paleta: <b>%s</b>\n🎲 Frecuencia: <b>%d%%</b>\n🌙 Horas de silencio: <b>%s</b>\n🪞 Modo: <b>%s</b>\n\n<i>Pulsa un botón para cambiar un ajuste.</i>",
+		"settings.on":             "SÍ ✅",
+		"settings.off":            "NO ❌",
+		"settings.in_topic":       " (este tema)",
+		"settings.quiet_off":      "no",
+		"settings.btn_on":         "💖 Reacciones: SÍ",
+		"settings.btn_off":        "💤 Reacciones: NO",
+		"settings.btn_palette":    "🎨 Paleta: %s",
+		"settings.btn_rate":       "🎲 Frecuencia: %d%%",
+		"settings.btn_quiet":      "🌙 Silencio: %s",
+		"settings.btn_mode":       "🪞 Modo: %s",
+		"settings.btn_close":      "✖️ Cerrar",
+		"settings.choose_palette": "🎨 <b>Elige una paleta</b>\n\n<i>Solo reaccionaré con sus emojis.</i>",
+		"settings.admins_only":    "🚫 ¡Solo los administradores pueden cambiar los ajustes!",
+		"settings.saved":          "✅ Guardado",
+		"settings.unknown":        "❓ Botón desconocido",
+
+		"stats.error":        "❌ No pude cargar las estadísticas, ¡inténtalo más tarde!",
+		"stats.empty":        "📊 <b>Estadísticas de reacciones</b>\n\n¡Aún no hay reacciones! Enviad algunos mensajes y me pondré manos a la obra ✨",
+		"stats.title":        "📊 <b>Estadísticas de reacciones de %s</b>",
+		"stats.total":        "💖 Reacciones totales: <b>%d</b>",
+		"stats.top_emojis":   "🏆 <b>Emojis más usados</b>",
+		"stats.last_week":    "📅 <b>Últimos 7 días</b>",
+		"stats.top_messages": "🔥 <b>Mensajes con más reacciones</b>",
+		"stats.message":      "Mensaje #%d",
+		"mystats.error":      "❌ No pude cargar tus estadísticas, ¡inténtalo más tarde!",
+		"mystats.empty":      "📊 Aún no he reaccionado a ninguno de tus mensajes aquí. ¡Sigue charlando! ✨",
+		"mystats.body":       "📊 <b>Estadísticas de %s</b>\n\n💖 Reacciones recibidas: <b>%d</b>\n😍 Emoji favorito: %s\n🏆 Puesto: <b>#%d</b> de %d",
+
+		"schedule.not_armed": "❌ Empieza con /broadcast y luego /schedule <cuándo>.",
+		"schedule.usage":     "🗓️ Uso: /schedule in 2h | 18:30 | 2026-12-24 18:00 | daily 09:00 | weekly mon 09:00 | every 6h",
+		"schedule.set":       "🗓️ El próximo mensaje se programará (%s), primera vez %s UTC.\nEnvíalo ahora, o /cancelbroadcast.",
+		"schedule.queued":    "✅ Programada #%d (%s), primera vez %s UTC. Ver /scheduled.",
+		"schedule.failed":    "❌ No se pudo programar la difusión.",
+		"schedule.passed":    "❌ %s ya ha pasado, no se programó nada. Empieza de nuevo con /broadcast.",
+		"scheduled.title":    "🗓️ <b>Difusiones programadas</b>",
+		"scheduled.item":     "<b>#%d</b> · próxima %s UTC · %s\n   🎯 %s\n   %s",
+		"scheduled.empty":    "<i>No hay nada programado.</i>",
+		"scheduled.hint":     "Cancela una con /unschedule &lt;id&gt;",
+		"unschedule.usage":   "❌ Uso: /unschedule <id>",
+		"unschedule.missing": "❌ No hay ninguna programación activa #%d.",
+		"unschedule.done":    "🛑 Programación #%d cancelada.",
+
+		"help.header": "📋 <b>Comandos</b>",
+
+		"member.need_admin": "👀 Aquí solo veo los comandos. ¡Hazme administrador para reaccionar a todos los mensajes!",
+		"member.promoted":   "✅ ¡Gracias por los permisos, ahora puedo reaccionar a todos los mensajes!",
+
+		"lang.current": "🌐 Idioma actual: %s\n\nElige otro con /lang seguido de un código:\n%s",
+		"lang.set":     "✅ ¡Idioma cambiado a %s!",
+		"lang.unknown": "❌ ¡Todavía no hablo ese idioma! Disponibles: %s",
+
+		"cmd.start":    "Mostrar mensaje de bienvenida",
+		"cmd.begin":    "Activar reacciones en el grupo",
+		"cmd.end":      "Detener reacciones en el grupo",
+		"cmd.ping":     "Comprobar mi tiempo de respuesta",
+		"cmd.status":   "Ver el diagnóstico detallado",
+		"cmd.settings": "Abrir el panel de ajustes",
+		"cmd.lang":     "Cambiar mi idioma",
+		"cmd.help":     "Ver los comandos disponibles",
+		"cmd.mode":     "Cambiar entre reacciones instantáneas y espejo",
+		"cmd.filter":   "Elegir qué mensajes reciben reacciones",
+		"cmd.everyone": "Permitir que todos cambien los ajustes",
+		"cmd.stats":    "Ver las estadísticas del grupo",
+		"cmd.mystats":  "Ver las reacciones que recibiste",
+	},
+	"ru": {
+		"lang.name": "🇷🇺 Русский",
+
+		"btn.updates":   "Новости",
+		"btn.support":   "Поддержка",
+		"btn.add_group": "Добавить меня в группу",
+		"btn.preset":    "🎨 Добавить с пресетом",
+		"btn.back":      "⬅️ Назад",
+
+		"scope.group": "группе",
+		"scope.topic": "теме",
+
+		"error.group_only":   "❌ Эта команда работает только в группах!",
+		"error.admin_only":   "🚫 Эту команду могут использовать только администраторы!",
+		"error.private_only": "❌ Эта команда работает только в личном чате!",
+		"error.owner_only":   "🚫 Это может только владелец бота!",
+
+		"broadcast.activated":     "🚀 *Режим рассылки включён!* 🚀\n\nОтправь любое сообщение. Я покажу превью, а после подтверждения доставлю его один раз каждому подписчику.\n\nДля отмены отправь /cancelbroadcast",
+		"broadcast.deactivated":   "🛑 *Режим рассылки выключен.*",
+		"broadcast.summary":       "📊 *Рассылка завершена!*\n\n✅ Успешно: %d\n❌ Ошибок: %d\n🤖 Ботов: %d\n👥 Подписчиков: %d",
+		"broadcast.audience":      "🎯 Аудитория: `%s`\n👥 Подходит %d из %d подписчиков",
+		"broadcast.progress":      "📢 Рассылка...\n\n✅ Отправлено: %d\n❌ Ошибок: %d\n⏳ Осталось: %d\n🕒 Ещё примерно: %s",
+		"broadcast.preview":       "👀 *Превью*\n\n🎯 Аудитория: `%s`\n👥 Чатов: %d\n\nПроверь сообщение ниже и выбери, как его отправить.",
+		"broadcast.btn_send":      "🚀 Отправить",
+		"broadcast.btn_silent":    "🔕 Отправить без звука",
+		"broadcast.btn_pin":       "📌 Закрепить после отправки: %s",
+		"broadcast.btn_cancel":    "✖️ Отмена",
+		"broadcast.pin_on":        "да",
+		"broadcast.pin_off":       "нет",
+		"broadcast.starting":      "🚀 Отправляю…",
+		"broadcast.cancelled":     "🛑 Рассылка отменена",
+		"broadcast.expired":       "⌛ Это превью устарело",
+		"broadcast.history":       "🗃️ <b>История рассылок</b>",
+		"broadcast.history_entry": "%s <b>#%d</b> · %s UTC\n   %s\n   ✅ отправлено: %d, ❌ ошибок: %d, ⏳ осталось: %d",
+		"broadcast.history_empty": "<i>Рассылок пока не было.</i>",
+		"tag.usage":               "❌ Использование: /%s <chat_id> <тег>...",
+		"tag.bad_chat":            "❌ Неверный ID чата: %s",
+		"tag.updated":             "✅ Теги чата %d обновлены: %s",
+		"tag.error":               "❌ Не удалось загрузить теги",
+		"tag.title":               "🏷️ Теги",
+		"tag.entry":               "• %s: чатов %d",
+		"tag.empty":               "Тегов пока нет. Добавь тег командой /tag <chat_id> <тег>",
+
+		"preset.applied":         "🎁 Пресет применён: %s",
+		"preset.unknown_palette": "❓ Неизвестная палитра",
+
+		"mode.current":     "🎛️ Текущий режим реакций: <b>%s</b>\n\n• /mode normal - Реагировать сразу после сообщения\n• /mode mirror - Повторять первую реакцию участника",
+		"mode.set":         "✅ Режим реакций: <b>%s</b>!",
+		"mode.mirror_hint": "\n\nЯ повторю первую реакцию на каждое сообщение или выберу свою через %s.",
+		"mode.need_admin":  "\n\n<i>Чтобы видеть реакции в группах, мне нужны права администратора 😉</i>",
+		"mode.unknown":     "❌ Неизвестный режим! Используй /mode normal или /mode mirror.",
+
+		"filter.panel":      "🧹 <b>Фильтры сообщений</b>\n\n%s Пропускать ботов\n%s Пропускать пересылки\n%s Пропускать служебные сообщения\n%s Только медиа\n📏 Минимальная длина текста: %s\n\n<i>Меняй через /filter bots off, /filter minlen 5, ...</i>",
+		"filter.off":        "выкл",
+		"filter.usage":      "❌ Использование: /filter &lt;bots|forwards|service|media&gt; &lt;on|off&gt; или /filter minlen &lt;число&gt;",
+		"filter.bad_minlen": "❌ Минимальная длина должна быть числом, например 3, или 0, чтобы отключить.",
+		"filter.bad_value":  "❌ Используй <code>on</code> или <code>off</code>.",
+		"filter.unknown":    "❌ Неизвестный фильтр! Доступны bots, forwards, service, media и minlen.",
+		"filter.updated":    "✅ Фильтры обновлены!",
+
+		"everyone.on":       "🔓 Теперь все могут использовать /begin, /end и другие команды настроек.",
+		"everyone.off":      "🔒 Теперь /begin, /end и другие команды настроек доступны только администраторам.",
+		"everyone.admins":   "только администраторы",
+		"everyone.everyone": "все",
+		"everyone.status":   "👮 Команды настроек сейчас доступны: <b>%s</b>\n\n• /everyone on - Разрешить всем участникам\n• /everyone off - Только администраторам",
+
+		"settings.panel":          "⚙️ <b>Настройки реакций</b>\n\n💖 Реакции: <b>%s</b>\n🎨 Палитра: <b>%s</b>\n🎲 Частота: <b>%d%%</b>\n🌙 Тихие часы: <b>%s</b>\n🪞 Режим: <b>%s</b>\n\n<i>Нажми кнопку, чтобы изменить настройку.</i>",
+		"settings.on":             "ВКЛ ✅",
+		"settings.off":            "ВЫКЛ ❌",
+		"settings.in_topic":       " (эта тема)",
+		"settings.quiet_off":      "выкл",
+		"settings.btn_on":         "💖 Реакции: ВКЛ",
+		"settings.btn_off":        "💤 Реакции: ВЫКЛ",
+		"settings.btn_palette":    "🎨 Палитра: %s",
+		"settings.btn_rate":       "🎲 Частота: %d%%",
+		"settings.btn_quiet":      "🌙 Тишина: %s",
+		"settings.btn_mode":       "🪞 Режим: %s",
+		"settings.btn_close":      "✖️ Закрыть",
+		"settings.choose_palette": "🎨 <b>Выбери палитру</b>\n\n<i>Я буду реагировать только её эмодзи.</i>",
+		"settings.admins_only":    "🚫 Менять настройки могут только администраторы!",
+		"settings.saved":          "✅ Сохранено",
+		"settings.unknown":        "❓ Неизвестная кнопка",
+
+		"stats.error":        "❌ Не удалось загрузить статистику, попробуй позже!",
+		"stats.empty":        "📊 <b>Статистика реакций</b>\n\nРеакций пока нет! Пишите сообщения, и я возьмусь за дело ✨",
+		"stats.title":        "📊 <b>Статистика реакций: %s</b>",
+		"stats.total":        "💖 Всего реакций: <b>%d</b>",
+		"stats.top_emojis":   "🏆 <b>Популярные эмодзи</b>",
+		"stats.last_week":    "📅 <b>Последние 7 дней</b>",
+		"stats.top_messages": "🔥 <b>Сообщения с наибольшим числом реакций</b>",
+		"stats.message":      "Сообщение #%d",
+		"mystats.error":      "❌ Не удалось загрузить твою статистику, попробуй позже!",
+		"mystats.empty":      "📊 Я ещё не реагировал на твои сообщения здесь. Продолжай общаться! ✨",
+		"mystats.body":       "📊 <b>Статистика: %s</b>\n\n💖 Получено реакций: <b>%d</b>\n😍 Любимый эмодзи: %s\n🏆 Место: <b>#%d</b> из %d",
+
+		"schedule.not_armed": "❌ Сначала /broadcast, затем /schedule <когда>.",
+		"schedule.usage":     "🗓️ Использование: /schedule in 2h | 18:30 | 2026-12-24 18:00 | daily 09:00 | weekly mon 09:00 | every 6h",
+		"schedule.set":       "🗓️ Следующее сообщение будет запланировано (%s), первый запуск %s UTC.\nОтправь его сейчас или /cancelbroadcast.",
+		"schedule.queued":    "✅ Запланировано #%d (%s), первый запуск %s UTC. Смотри /scheduled.",
+		"schedule.failed":    "❌ Не удалось запланировать рассылку.",
+		"schedule.passed":    "❌ Время %s уже прошло, ничего не запланировано. Начни заново с /broadcast.",
+		"scheduled.title":    "🗓️ <b>Запланированные рассылки</b>",
+		"scheduled.item":     "<b>#%d</b> · следующая %s UTC · %s\n   🎯 %s\n   %s",
+		"scheduled.empty":    "<i>Ничего не запланировано.</i>",
+		"scheduled.hint":     "Отменить: /unschedule &lt;id&gt;",
+		"unschedule.usage":   "❌ Использование: /unschedule <id>",
+		"unschedule.missing": "❌ Нет активного расписания #%d.",
+		"unschedule.done":    "🛑 Расписание #%d отменено.",
+
+		"help.header": "📋 <b>Команды</b>",
+
+		"member.need_admin": "👀 Здесь я вижу только команды. Сделайте меня администратором, чтобы я реагировал на все сообщения!",
+		"member.promoted":   "✅ Спасибо за права администратора, теперь я реагирую на все сообщения!",
+
+		"lang.current": "🌐 Текущий язык: %s\n\nВыбери другой командой /lang с кодом языка:\n%s",
+		"lang.set":     "✅ Язык изменён на %s!",
+		"lang.unknown": "❌ Этот язык я пока не знаю! Доступны: %s",
+
+		"cmd.start":    "Показать приветствие",
+		"cmd.begin":    "Включить реакции в группе",
+		"cmd.end":      "Выключить реакции в группе",
+		"cmd.ping":     "Проверить скорость ответа",
+		"cmd.status":   "Подробная диагностика",
+		"cmd.settings": "Открыть панель настроек",
+		"cmd.lang":     "Сменить язык",
+		"cmd.help":     "Список доступных команд",
+		"cmd.mode":     "Переключить мгновенные и зеркальные реакции",
+		"cmd.filter":   "Выбрать, на какие сообщения реагировать",
+		"cmd.everyone": "Разрешить всем менять настройки",
+		"cmd.stats":    "Статистика реакций группы",
+		"cmd.mystats":  "Реакции, которые получил ты",
+	},
+}
+
+var (
+	chatLanguages = make(map[int64]string) // chatID -> language chosen with /lang
+	langMutex     sync.RWMutex             // protects chatLanguages
+)
+
+// T looks up key in lang, falling back to defaultLang, and formats it
+// with args when any are given.
+func T(lang, key string, args ...interface{}) string {
+	text, ok := catalog[lang][key]
+	if !ok {
+		text, ok = catalog[defaultLang][key]
+		if !ok {
+			log.Printf(ColorRed+"⚠️  Missing message key %q"+ColorReset, key)
+			return key
+		}
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(text, args...)
+	}
+	return text
+}
+
+// normalizeLang reduces an IETF tag like "pt-BR" to a catalog code, or ""
+// when we have no such locale.
+func normalizeLang(code string) string {
+	code = strings.ToLower(code)
+	if i := strings.IndexAny(code, "-_"); i >= 0 {
+		code = code[:i]
+	}
+	if _, ok := catalog[code]; ok {
+		return code
+	}
+	return ""
+}
+
+// langFor picks the language for a reply: the chat's /lang choice first,
+// then the sender's Telegram language, then defaultLang.
+func langFor(msg *tgbotapi.Message) string {
+	langMutex.RLock()
+	lang, ok := chatLanguages[msg.Chat.ID]
+	langMutex.RUnlock()
+	if ok {
+		return lang
+	}
+	if msg.From != nil {
+		if lang := normalizeLang(msg.From.LanguageCode); lang != "" {
+			return lang
+		}
+	}
+	return defaultLang
+}
+
+func setChatLanguage(chatID int64, lang string) {
+	langMutex.Lock()
+	chatLanguages[chatID] = lang
+	langMutex.Unlock()
+	storeChatLanguage(chatID, lang)
+	log.Printf(ColorBlue+"🌐 Language %s for chat %d"+ColorReset, lang, chatID)
+}
+
+func availableLangs() []string {
+	codes := make([]string, 0, len(catalog))
+	for code := range catalog {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// ─── /lang Command ───────────────────────
+func handleLangCommand(bot *tgbotapi.BotAPI, msg *tgbotapi.Message) {
+	arg := strings.TrimSpace(msg.CommandArguments())
+	lang := langFor(msg)
+
+	var text string
+	if arg == "" {
+		var list strings.Builder
+		for _, code := range availableLangs() {
+			fmt.Fprintf(&list, "• <code>%s</code> %s\n", code, T(code, "lang.name"))
+		}
+		text = T(lang, "lang.current", T(lang, "lang.name"), list.String())
+	} else if code := normalizeLang(arg); code != "" {
+		if !requireAdmin(bot, msg) {
+			return
+		}
+		setChatLanguage(msg.Chat.ID, code)
+		text = T(code, "lang.set", T(code, "lang.name"))
+	} else {
+		text = T(lang, "lang.unknown", strings.Join(availableLangs(), ", "))
+	}
+
+	cfg := tgbotapi.NewMessage(msg.Chat.ID, text)
+	cfg.ParseMode = "HTML"
+	if _, err := bot.Send(cfg); err != nil {
+		logError("langCommand", bot.Self.UserName, err)
+	}
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// ─── Inline Picker ───────────────────────
+// "@bot party" offers emoji combos from every palette and seasonal theme
+// matching the query. Inline mode has to be enabled with @BotFather.
+// Combos are random, so results are cached per query to keep them from
+// reshuffling while the user is still typing.
+const (
+	inlineCombos    = 3  // random combos per emoji set
+	inlineComboSize = 3  // emojis per combo
+	inlineMaxResult = 50 // Telegram's limit per answer
+)
+
+var (
+	inlineCache    = make(map[string]inlineEntry) // normalized query -> results
+	inlineMutex    sync.Mutex                     // protects inlineCache
+	inlineCacheTTL = 10 * time.Minute
+	inlineCacheMax = 500
+)
+
+type inlineEntry struct {
+	results []interface{}
+	expires time.Time
+}
+
+func handleInlineQuery(bot *tgbotapi.BotAPI, q *tgbotapi.InlineQuery) {
+	query := strings.ToLower(strings.TrimSpace(q.Query))
+	log.Printf(ColorBlue+"🔎 Inline query %q from @%s"+ColorReset, query, q.From.UserName)
+
+	cfg := tgbotapi.InlineConfig{
+		InlineQueryID: q.ID,
+		Results:       inlineResults(query, time.Now()),
+		CacheTime:     int(inlineCacheTTL / time.Second),
+	}
+	if _, err := bot.Request(cfg); err != nil {
+		logError("answerInlineQuery", bot.Self.UserName, err)
+	}
+}
+
+// inlineResults returns the cached results for query, building them when
+// missing or expired.
+func inlineResults(query string, now time.Time) []interface{} {
+	inlineMutex.Lock()
+	defer inlineMutex.Unlock()
+
+	if e, ok := inlineCache[query]; ok && now.Before(e.expires) {
+		return e.results
+	}
+	if len(inlineCache) >= inlineCacheMax {
+		for k, e := range inlineCache {
+			if !now.Before(e.expires) {
+				delete(inlineCache, k)
+			}
+		}
+		if len(inlineCache) >= inlineCacheMax {
+			inlineCache = make(map[string]inlineEntry)
+		}
+	}
+
+	results := buildInlineResults(matchEmojiSets(query, now))
+	inlineCache[query] = inlineEntry{results: results, expires: now.Add(inlineCacheTTL)}
+	return results
+}
+
+// matchEmojiSets finds the sets a query asks for, themes in season first.
+// An empty or unmatched query gets everything.
+func matchEmojiSets(query string, now time.Time) []palette {
+	sets := inSeason(now)
+	seen := make(map[string]bool)
+	for _, p := range sets {
+		seen[p.Name] = true
+	}
+	for _, p := range append(append([]palette{}, palettes...), seasonalThemes...) {
+		if !seen[p.Name] {
+			sets = append(sets, p)
+			seen[p.Name] = true
+		}
+	}
+	if query == "" {
+		return sets
+	}
+
+	var matched []palette
+	for _, p := range sets {
+		if paletteMatches(p, query) {
+			matched = append(matched, p)
+		}
+	}
+	if len(matched) == 0 {
+		return sets
+	}
+	return matched
+}
+
+func paletteMatches(p palette, query string) bool {
+	if strings.HasPrefix(p.Name, query) || strings.Contains(strings.ToLower(p.Label), query) {
+		return true
+	}
+	for _, kw := range p.Keywords {
+		if strings.HasPrefix(kw, query) {
+			return true
+		}
+	}
+	for _, e := range p.Emojis {
+		if strings.Contains(query, e) {
+			return true
+		}
+	}
+	return false
+}
+
+func buildInlineResults(sets []palette) []interface{} {
+	var results []interface{}
+	for _, p := range sets {
+		all := tgbotapi.NewInlineQueryResultArticle(p.Name+"-all", p.Label, strings.Join(p.Emojis, ""))
+		all.Description = strings.Join(p.Emojis, " ")
+		results = append(results, all)
+
+		for i := 0; i < inlineCombos; i++ {
+			combo := randomCombo(p.Emojis, inlineComboSize)
+			article := tgbotapi.NewInlineQueryResultArticle(fmt.Sprintf("%s-%d", p.Name, i), combo, combo)
+			article.Description = p.Label
+			results = append(results, article)
+		}
+		if len(results) >= inlineMaxResult {
+			return results[:inlineMaxResult]
+		}
+	}
+	return results
+}
+
+// randomCombo joins n distinct emojis from set.
+func randomCombo(set []string, n int) string {
+	if n > len(set) {
+		n = len(set)
+	}
+	var b strings.Builder
+	for _, i := range rand.Perm(len(set))[:n] {
+		b.WriteString(set[i])
+	}
+	return b.String()
+}
@@ -0,0 +1,274 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"html"
+	"log"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// ─── Broadcast Job Store ─────────────────
+// Every broadcast is a row in broadcast_jobs with one broadcast_deliveries
+// row per recipient. A delivery is marked "sending" before the request
+// goes out, so after a crash a chat is never sent the message twice:
+// "sending" rows become "unknown" and the rest of the job resumes when
+// its bot starts again.
+const (
+	deliveryPending = "pending"
+	deliverySending = "sending"
+	deliverySent    = "sent"
+	deliveryFailed  = "failed"
+	deliveryUnknown = "unknown"
+
+	jobRunning = "running"
+	jobDone    = "done"
+)
+
+// createBroadcastJob stores a new job for msg and its recipients.
+// When via is set, only that bot delivers.
+func createBroadcastJob(origin *tgbotapi.BotAPI, msg *tgbotapi.Message, lang string, recipients []int64, via *tgbotapi.BotAPI, opts broadcastOptions) (*broadcastJob, error) {
+	entities, err := json.Marshal(msg.Entities)
+	if err != nil {
+		return nil, fmt.Errorf("encode entities: %w", err)
+	}
+	var viaBotID int64
+	if via != nil {
+		viaBotID = via.Self.ID
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("begin: %w", err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec(`INSERT INTO broadcast_jobs (origin_bot_id, from_chat_id, message_id, text, entities, lang, status, via_bot_id, silent, pin)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		origin.Self.ID, msg.Chat.ID, msg.MessageID, msg.Text, string(entities), lang, jobRunning, viaBotID, opts.Silent, opts.Pin)
+	if err != nil {
+		return nil, fmt.Errorf("insert job: %w", err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("job id: %w", err)
+	}
+
+	stmt, err := tx.Prepare(`INSERT INTO broadcast_deliveries (job_id, chat_id, status) VALUES (?, ?, ?)`)
+	if err != nil {
+		return nil, fmt.Errorf("prepare deliveries: %w", err)
+	}
+	defer stmt.Close()
+	for _, chatID := range recipients {
+		if _, err := stmt.Exec(id, chatID, deliveryPending); err != nil {
+			return nil, fmt.Errorf("insert delivery: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit: %w", err)
+	}
+
+	log.Printf(ColorBlue+"🗃️  Broadcast job #%d created for %d recipients"+ColorReset, id, len(recipients))
+	return &broadcastJob{
+		id:       id,
+		origin:   origin,
+		msg:      msg,
+		lang:     lang,
+		total:    len(recipients),
+		pending:  recipients,
+		viaBotID: viaBotID,
+		opts:     opts,
+	}, nil
+}
+
+func saveJobProgress(jobID int64, progress tgbotapi.Message) {
+	_, err := db.Exec(`UPDATE broadcast_jobs SET progress_chat_id = ?, progress_message_id = ? WHERE id = ?`,
+		progress.Chat.ID, progress.MessageID, jobID)
+	if err != nil {
+		logError("saveJobProgress", fmt.Sprint(jobID), err)
+	}
+}
+
+func markDelivery(jobID, chatID int64, status string, botID int64, sendErr error) {
+	errText := ""
+	if sendErr != nil {
+		errText = sendErr.Error()
+	}
+	_, err := db.Exec(`UPDATE broadcast_deliveries SET status = ?, bot_id = ?, error = ?, updated_at = CURRENT_TIMESTAMP
+		WHERE job_id = ? AND chat_id = ?`, status, botID, errText, jobID, chatID)
+	if err != nil {
+		logError("markDelivery", fmt.Sprint(jobID), err)
+	}
+}
+
+func finishJob(jobID int64) {
+	_, err := db.Exec(`UPDATE broadcast_jobs SET status = ?, finished_at = CURRENT_TIMESTAMP WHERE id = ?`, jobDone, jobID)
+	if err != nil {
+		logError("finishJob", fmt.Sprint(jobID), err)
+	}
+}
+
+// ─── Resuming Jobs ───────────────────────
+// resumeBroadcasts restarts the unfinished jobs bot was running when the
+// process stopped.
+func resumeBroadcasts(bot *tgbotapi.BotAPI) {
+	jobs, err := loadRunningJobs(bot)
+	if err != nil {
+		logError("resumeBroadcasts", bot.Self.UserName, err)
+		return
+	}
+	for _, job := range jobs {
+		log.Printf(ColorCyan+"♻️  Resuming broadcast #%d: %d of %d left"+ColorReset, job.id, len(job.pending), job.total)
+		go job.run()
+	}
+}
+
+func loadRunningJobs(bot *tgbotapi.BotAPI) ([]*broadcastJob, error) {
+	rows, err := db.Query(`SELECT id, from_chat_id, message_id, text, entities, lang, progress_chat_id, progress_message_id, via_bot_id, silent, pin
+		FROM broadcast_jobs WHERE status = ? AND origin_bot_id = ?`, jobRunning, bot.Self.ID)
+	if err != nil {
+		return nil, fmt.Errorf("query jobs: %w", err)
+	}
+	defer rows.Close()
+
+	var jobs []*broadcastJob
+	for rows.Next() {
+		var (
+			fromChat, progressChat int64
+			msgID, progressMsg     int
+			entities               string
+			job                    = &broadcastJob{origin: bot, msg: &tgbotapi.Message{}}
+		)
+		if err := rows.Scan(&job.id, &fromChat, &msgID, &job.msg.Text, &entities, &job.lang, &progressChat, &progressMsg, &job.viaBotID,
+			&job.opts.Silent, &job.opts.Pin); err != nil {
+			return nil, fmt.Errorf("scan job: %w", err)
+		}
+		job.msg.Chat = &tgbotapi.Chat{ID: fromChat}
+		job.msg.MessageID = msgID
+		if err := json.Unmarshal([]byte(entities), &job.msg.Entities); err != nil {
+			return nil, fmt.Errorf("decode entities of job %d: %w", job.id, err)
+		}
+		if progressMsg != 0 {
+			job.progress = tgbotapi.Message{Chat: &tgbotapi.Chat{ID: progressChat}, MessageID: progressMsg}
+		}
+		jobs = append(jobs, job)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("query jobs: %w", err)
+	}
+	rows.Close()
+
+	for _, job := range jobs {
+		if err := loadDeliveries(job); err != nil {
+			return nil, err
+		}
+	}
+	return jobs, nil
+}
+
+// loadDeliveries fills in a resumed job's counters and pending chats.
+// Whatever was in flight when the process died may or may not have
+// arrived, so it is not retried.
+func loadDeliveries(job *broadcastJob) error {
+	_, err := db.Exec(`UPDATE broadcast_deliveries SET status = ? WHERE job_id = ? AND status = ?`,
+		deliveryUnknown, job.id, deliverySending)
+	if err != nil {
+		return fmt.Errorf("mark unknown deliveries of job %d: %w", job.id, err)
+	}
+
+	rows, err := db.Query(`SELECT chat_id, status FROM broadcast_deliveries WHERE job_id = ?`, job.id)
+	if err != nil {
+		return fmt.Errorf("query deliveries of job %d: %w", job.id, err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			chatID int64
+			status string
+		)
+		if err := rows.Scan(&chatID, &status); err != nil {
+			return fmt.Errorf("scan delivery: %w", err)
+		}
+		job.total++
+		switch status {
+		case deliveryPending:
+			job.pending = append(job.pending, chatID)
+		case deliverySent:
+			job.sent.Add(1)
+		default:
+			job.failed.Add(1)
+		}
+	}
+	return rows.Err()
+}
+
+// ─── Job History ─────────────────────────
+func handleBroadcastsCommand(c *commandContext) {
+	const limit = 10
+	rows, err := db.Query(`SELECT j.id, j.created_at, j.status, j.text,
+			COUNT(d.chat_id) FILTER (WHERE d.status = ?),
+			COUNT(d.chat_id) FILTER (WHERE d.status IN (?, ?)),
+			COUNT(d.chat_id) FILTER (WHERE d.status IN (?, ?))
+		FROM broadcast_jobs j LEFT JOIN broadcast_deliveries d ON d.job_id = j.id
+		GROUP BY j.id ORDER BY j.id DESC LIMIT ?`,
+		deliverySent, deliveryFailed, deliveryUnknown, deliveryPending, deliverySending, limit)
+	if err != nil {
+		logError("broadcastHistory", c.Bot.Self.UserName, err)
+		return
+	}
+	defer rows.Close()
+
+	var b strings.Builder
+	b.WriteString(T(c.Lang, "broadcast.history") + "\n\n")
+	count := 0
+	for rows.Next() {
+		var (
+			id                   int64
+			created              time.Time
+			status               string
+			text                 sql.NullString
+			sent, failed, queued int
+		)
+		if err := rows.Scan(&id, &created, &status, &text, &sent, &failed, &queued); err != nil {
+			logError("broadcastHistory", c.Bot.Self.UserName, err)
+			return
+		}
+		icon := "✅"
+		if status == jobRunning {
+			icon = "🔄"
+		}
+		b.WriteString(T(c.Lang, "broadcast.history_entry",
+			icon, id, created.UTC().Format("2006-01-02 15:04"), html.EscapeString(preview(text.String)), sent, failed, queued) + "\n")
+		count++
+	}
+	if err := rows.Err(); err != nil {
+		logError("broadcastHistory", c.Bot.Self.UserName, err)
+		return
+	}
+	if count == 0 {
+		b.WriteString(T(c.Lang, "broadcast.history_empty"))
+	}
+
+	cfg := tgbotapi.NewMessage(c.Msg.Chat.ID, b.String())
+	cfg.ParseMode = "HTML"
+	if _, err := c.Bot.Send(cfg); err != nil {
+		logError("broadcastHistory", c.Bot.Self.UserName, err)
+	}
+}
+
+// preview shortens a broadcast text for lists.
+func preview(text string) string {
+	const max = 40
+	if text == "" {
+		return "📎 media"
+	}
+	text = strings.Join(strings.Fields(text), " ")
+	if r := []rune(text); len(r) > max {
+		return string(r[:max]) + "…"
+	}
+	return text
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// ─── Media Library ───────────────────────
+// Images in MEDIA_DIR are uploaded once per bot; the file_id Telegram
+// returns is cached in media_cache and reused afterwards. SAKURA_IMAGES
+// is only used when the local library is empty and REMOTE_IMAGES is on.
+var (
+	mediaDir     = getEnv("MEDIA_DIR", "./media")
+	remoteImages = getEnv("REMOTE_IMAGES", "on") != "off"
+
+	errNoMedia = errors.New("no images available")
+)
+
+var mediaExts = map[string]bool{".jpg": true, ".jpeg": true, ".png": true, ".webp": true}
+
+// libraryImage is a local image together with the facts used to tell
+// whether its cached file_id is still valid.
+type libraryImage struct {
+	Name    string
+	Path    string
+	ModTime int64
+	Size    int64
+}
+
+func localImages() []libraryImage {
+	entries, err := os.ReadDir(mediaDir)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			logError("read media dir", mediaDir, err)
+		}
+		return nil
+	}
+
+	var images []libraryImage
+	for _, e := range entries {
+		if e.IsDir() || !mediaExts[strings.ToLower(filepath.Ext(e.Name()))] {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		images = append(images, libraryImage{
+			Name:    e.Name(),
+			Path:    filepath.Join(mediaDir, e.Name()),
+			ModTime: info.ModTime().Unix(),
+			Size:    info.Size(),
+		})
+	}
+	return images
+}
+
+// cachedFileID returns the stored file_id for img, or "" when the image
+// was never uploaded by this bot or has changed on disk since.
+func cachedFileID(botID int64, img libraryImage) string {
+	var fileID string
+	err := db.QueryRow(`SELECT file_id FROM media_cache
+		WHERE bot_id = ? AND name = ? AND mod_time = ? AND size = ?`,
+		botID, img.Name, img.ModTime, img.Size).Scan(&fileID)
+	if err != nil && err != sql.ErrNoRows {
+		logError("media cache lookup", img.Name, err)
+	}
+	return fileID
+}
+
+func storeFileID(botID int64, img libraryImage, fileID string) {
+	_, err := db.Exec(`INSERT INTO media_cache (bot_id, name, mod_time, size, file_id) VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (bot_id, name) DO UPDATE SET mod_time = excluded.mod_time, size = excluded.size,
+			file_id = excluded.file_id, uploaded_at = CURRENT_TIMESTAMP`,
+		botID, img.Name, img.ModTime, img.Size, fileID)
+	if err != nil {
+		logError("media cache store", img.Name, err)
+		return
+	}
+	log.Printf(ColorGreen+"🗂️  Cached file_id for %s"+ColorReset, img.Name)
+}
+
+func forgetFileID(botID int64, name string) {
+	if _, err := db.Exec(`DELETE FROM media_cache WHERE bot_id = ? AND name = ?`, botID, name); err != nil {
+		logError("media cache delete", name, err)
+	}
+}
+
+// sendLibraryPhoto fills in a random image and sends photo. It returns
+// errNoMedia when there is nothing to send, so callers can fall back to
+// text the same way as for any other send error.
+func sendLibraryPhoto(bot *tgbotapi.BotAPI, photo tgbotapi.PhotoConfig) (tgbotapi.Message, error) {
+	images := localImages()
+	if len(images) == 0 {
+		if !remoteImages || len(SAKURA_IMAGES) == 0 {
+			return tgbotapi.Message{}, errNoMedia
+		}
+		url := SAKURA_IMAGES[rand.Intn(len(SAKURA_IMAGES))]
+		log.Printf(ColorCyan+"🌸 Selected remote Sakura image: %s"+ColorReset, url)
+		photo.File = tgbotapi.FileURL(url)
+		return bot.Send(photo)
+	}
+
+	img := images[rand.Intn(len(images))]
+	fileID := cachedFileID(bot.Self.ID, img)
+	if fileID != "" {
+		log.Printf(ColorCyan+"🌸 Selected cached image: %s"+ColorReset, img.Name)
+		photo.File = tgbotapi.FileID(fileID)
+	} else {
+		log.Printf(ColorCyan+"🌸 Uploading image: %s"+ColorReset, img.Name)
+		photo.File = tgbotapi.FilePath(img.Path)
+	}
+
+	sent, err := bot.Send(photo)
+	if err != nil {
+		if fileID != "" {
+			// The file_id may have expired, upload again next time
+			forgetFileID(bot.Self.ID, img.Name)
+		}
+		return sent, fmt.Errorf("send %s: %w", img.Name, err)
+	}
+	if fileID == "" && len(sent.Photo) > 0 {
+		storeFileID(bot.Self.ID, img, sent.Photo[len(sent.Photo)-1].FileID)
+	}
+	return sent, nil
+}
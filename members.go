@@ -0,0 +1,92 @@
+package main
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// ─── Membership Updates ──────────────────
+// my_chat_member tells us when the bot itself joins, leaves or changes
+// role in a chat, and when a user blocks or unblocks it in private.
+var (
+	welcomedAt    = make(map[int64]time.Time) // chatID -> last group welcome
+	welcomeMutex  sync.Mutex                  // protects welcomedAt
+	welcomeWindow = time.Minute
+)
+
+// markWelcomed notes a group welcome, so the /start Telegram sends for a
+// startgroup link right after joining does not greet twice.
+func markWelcomed(chatID int64) {
+	welcomeMutex.Lock()
+	defer welcomeMutex.Unlock()
+	welcomedAt[chatID] = time.Now()
+}
+
+func recentlyWelcomed(chatID int64) bool {
+	welcomeMutex.Lock()
+	defer welcomeMutex.Unlock()
+	return time.Since(welcomedAt[chatID]) < welcomeWindow
+}
+
+func isPresent(m tgbotapi.ChatMember) bool {
+	switch m.Status {
+	case "creator", "administrator", "member":
+		return true
+	case "restricted":
+		return m.IsMember
+	default:
+		return false
+	}
+}
+
+func handleMyChatMember(bot *tgbotapi.BotAPI, u *tgbotapi.ChatMemberUpdated) {
+	chat := &u.Chat
+	before, after := u.OldChatMember, u.NewChatMember
+	log.Printf(ColorBlue+"👥 Membership in %d (%s): %s -> %s by @%s"+ColorReset,
+		chat.ID, chat.Type, before.Status, after.Status, u.From.UserName)
+
+	switch {
+	case isPresent(before) && !isPresent(after):
+		removeSubscriberBot(bot.Self.ID, chat.ID)
+		log.Printf(ColorYellow+"👋 @%s removed from %d"+ColorReset, bot.Self.UserName, chat.ID)
+
+	case !isPresent(before) && isPresent(after):
+		total := addSubscriber(bot.Self.ID, chat, &u.From)
+		log.Printf(ColorGreen+"➕ Added to %d (%s), now %d subscribers"+ColorReset, chat.ID, chat.Title, total)
+		if isGroup(chat) {
+			msg := &tgbotapi.Message{Chat: chat, From: &u.From}
+			sendGroupWelcome(bot, msg)
+			checkPermissions(bot, msg, after)
+		}
+
+	case isGroup(chat) && after.Status != before.Status:
+		// Promoted or demoted while staying in the group
+		checkPermissions(bot, &tgbotapi.Message{Chat: chat, From: &u.From}, after)
+	}
+}
+
+// checkPermissions warns the group when the bot cannot do its job. Without
+// admin rights a bot in privacy mode only sees commands.
+func checkPermissions(bot *tgbotapi.BotAPI, msg *tgbotapi.Message, self tgbotapi.ChatMember) {
+	if self.Status == "restricted" && !self.CanSendMessages {
+		log.Printf(ColorYellow+"🔇 Cannot send messages in %d"+ColorReset, msg.Chat.ID)
+		return
+	}
+
+	key := "member.promoted"
+	if !self.IsAdministrator() {
+		if bot.Self.CanReadAllGroupMessages {
+			return
+		}
+		key = "member.need_admin"
+	}
+	log.Printf(ColorCyan+"🔐 Permissions in %d: %s"+ColorReset, msg.Chat.ID, key)
+
+	cfg := tgbotapi.NewMessage(msg.Chat.ID, T(langFor(msg), key))
+	if _, err := bot.Send(cfg); err != nil {
+		logError("checkPermissions", bot.Self.UserName, err)
+	}
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"sync"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// ─── Supergroup Migration ────────────────
+// Upgrading a group to a supergroup gives it a new chat ID. Telegram posts
+// migrate_to_chat_id in the old chat and migrate_from_chat_id in the new
+// one, and every bot in the group sees both, so migrateChat has to be
+// idempotent: whatever is left under the old ID is moved, then removed.
+var migrationMutex sync.Mutex // serializes migrations
+
+// migrationOf returns the old and new chat ID when msg announces a
+// migration.
+func migrationOf(msg *tgbotapi.Message) (from, to int64, ok bool) {
+	switch {
+	case msg.MigrateToChatID != 0:
+		return msg.Chat.ID, msg.MigrateToChatID, true
+	case msg.MigrateFromChatID != 0:
+		return msg.MigrateFromChatID, msg.Chat.ID, true
+	default:
+		return 0, 0, false
+	}
+}
+
+// migrateChat moves everything stored for chat from to chat to. Stats,
+// subscriber, tag and settings rows move in one transaction first; the
+// in-memory settings only move once that has committed, with all their
+// locks held so no reader sees a half moved chat.
+func migrateChat(from, to int64) error {
+	migrationMutex.Lock()
+	defer migrationMutex.Unlock()
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("begin: %w", err)
+	}
+	res, err := tx.Exec(`UPDATE reactions SET chat_id = ? WHERE chat_id = ?`, to, from)
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("move reactions: %w", err)
+	}
+	for _, stmt := range []string{
+		`UPDATE OR IGNORE subscribers SET chat_id = ?, type = 'supergroup' WHERE chat_id = ?`,
+		`UPDATE OR IGNORE subscriber_bots SET chat_id = ? WHERE chat_id = ?`,
+		`UPDATE OR IGNORE chat_tags SET chat_id = ? WHERE chat_id = ?`,
+		`UPDATE OR IGNORE chat_settings SET chat_id = ? WHERE chat_id = ?`,
+		`UPDATE OR IGNORE chat_topic_settings SET chat_id = ? WHERE chat_id = ?`,
+		`UPDATE OR IGNORE message_reactions SET chat_id = ? WHERE chat_id = ?`,
+	} {
+		if _, err := tx.Exec(stmt, to, from); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("move subscriber: %w", err)
+		}
+	}
+	// Rows left behind clashed with ones the new chat already has
+	for _, stmt := range []string{
+		`DELETE FROM subscribers WHERE chat_id = ?`,
+		`DELETE FROM subscriber_bots WHERE chat_id = ?`,
+		`DELETE FROM chat_tags WHERE chat_id = ?`,
+		`DELETE FROM chat_settings WHERE chat_id = ?`,
+		`DELETE FROM chat_topic_settings WHERE chat_id = ?`,
+		`DELETE FROM message_reactions WHERE chat_id = ?`,
+	} {
+		if _, err := tx.Exec(stmt, from); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("drop old subscriber: %w", err)
+		}
+	}
+	_, err = tx.Exec(`UPDATE subscribers SET inactive = 0
+		WHERE chat_id = ? AND EXISTS (SELECT 1 FROM subscriber_bots WHERE chat_id = subscribers.chat_id)`, to)
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("reactivate subscriber: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+	rows, _ := res.RowsAffected()
+
+	reactionMutex.Lock()
+	langMutex.Lock()
+	adminMutex.Lock()
+	subMutex.Lock()
+	moveKey(groupReactions, from, to)
+	moveKey(reactionModes, from, to)
+	moveKey(chatFilters, from, to)
+	moveKey(chatPalettes, from, to)
+	moveKey(chatRates, from, to)
+	moveKey(chatQuiet, from, to)
+	for k, v := range topicReactions {
+		if k.chatID != from {
+			continue
+		}
+		moved := topicKey{chatID: to, threadID: k.threadID}
+		if _, exists := topicReactions[moved]; !exists {
+			topicReactions[moved] = v
+		}
+		delete(topicReactions, k)
+	}
+	moveKey(chatLanguages, from, to)
+	moveKey(openToggles, from, to)
+	delete(adminCache, from)
+	if sub, ok := subscribers[from]; ok {
+		if existing, exists := subscribers[to]; exists {
+			// Bots seen in either chat are in the supergroup now,
+			// like the merged subscriber_bots rows
+			if existing.Bots == nil {
+				existing.Bots = make(map[int64]bool)
+			}
+			for id := range sub.Bots {
+				existing.Bots[id] = true
+			}
+			existing.Inactive = len(existing.Bots) == 0
+			subscribers[to] = existing
+		} else {
+			sub.Type = "supergroup"
+			subscribers[to] = sub
+		}
+		delete(subscribers, from)
+	}
+	subMutex.Unlock()
+	adminMutex.Unlock()
+	langMutex.Unlock()
+	reactionMutex.Unlock()
+
+	log.Printf(ColorGreen+"🔀 Migrated chat %d -> %d (%d reactions)"+ColorReset, from, to, rows)
+	return nil
+}
+
+// moveKey moves m[from] to m[to] when present. Settings made in the new
+// chat in the meantime win over the old ones.
+func moveKey[V any](m map[int64]V, from, to int64) {
+	v, ok := m[from]
+	if !ok {
+		return
+	}
+	if _, exists := m[to]; !exists {
+		m[to] = v
+	}
+	delete(m, from)
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// ─── Reaction Modes ──────────────────────
+const (
+	ModeNormal = "normal" // react as soon as a message arrives
+	ModeMirror = "mirror" // echo the first human reaction, random after timeout
+)
+
+var (
+	reactionModes = make(map[int64]string) // chatID -> reaction mode, guarded by reactionMutex
+
+	mirrorTimeout = 90 * time.Second // how long to wait for a human reaction
+	pendingMirror = make(map[mirrorKey]pendingReaction)
+	mirrorMutex   sync.Mutex // protects pendingMirror
+)
+
+// mirrorKey identifies a message waiting for its first human reaction.
+// The bot ID is part of the key because every bot in a shared chat sees
+// the same message.
+type mirrorKey struct {
+	botID  int64
+	chatID int64
+	msgID  int
+}
+
+// pendingReaction is a parked message. The sender is kept because
+// reaction updates do not say who wrote the message.
+type pendingReaction struct {
+	timer    *time.Timer
+	senderID int64
+}
+
+func getReactionMode(chatID int64) string {
+	reactionMutex.RLock()
+	defer reactionMutex.RUnlock()
+	if mode, ok := reactionModes[chatID]; ok {
+		return mode
+	}
+	return ModeNormal
+}
+
+func setReactionMode(chatID int64, mode string) {
+	reactionMutex.Lock()
+	defer reactionMutex.Unlock()
+	reactionModes[chatID] = mode
+	log.Printf(ColorBlue+"🎛️  Reaction mode %s for chat %d"+ColorReset, mode, chatID)
+}
+
+// ─── Mirror Scheduling ───────────────────
+// scheduleMirror parks msg until a human reacts to it or mirrorTimeout
+// passes, whichever comes first.
+func scheduleMirror(bot *tgbotapi.BotAPI, msg *tgbotapi.Message) {
+	key := mirrorKey{botID: bot.Self.ID, chatID: msg.Chat.ID, msgID: msg.MessageID}
+	log.Printf(ColorCyan+"🪞 Waiting up to %s for a human reaction on msg %d in chat %d"+ColorReset, mirrorTimeout, msg.MessageID, msg.Chat.ID)
+
+	mirrorMutex.Lock()
+	defer mirrorMutex.Unlock()
+	if _, exists := pendingMirror[key]; exists {
+		return
+	}
+	timer := time.AfterFunc(mirrorTimeout, func() {
+		mirrorMutex.Lock()
+		_, still := pendingMirror[key]
+		delete(pendingMirror, key)
+		mirrorMutex.Unlock()
+		if !still {
+			return
+		}
+		log.Printf(ColorYellow+"⏰ No human reaction on msg %d in chat %d, falling back"+ColorReset, msg.MessageID, msg.Chat.ID)
+		reactToMessage(bot, msg)
+	})
+	pendingMirror[key] = pendingReaction{timer: timer, senderID: msg.From.ID}
+}
+
+// handleReactionUpdate mirrors the first emoji a human puts on a pending
+// message.
+func handleReactionUpdate(bot *tgbotapi.BotAPI, upd *messageReactionUpdated) {
+	if upd.User == nil || upd.User.IsBot {
+		return
+	}
+
+	emoji := ""
+	for _, r := range upd.NewReaction {
+		if r.Type == "emoji" && r.Emoji != "" {
+			emoji = r.Emoji
+			break
+		}
+	}
+	if emoji == "" {
+		return
+	}
+
+	key := mirrorKey{botID: bot.Self.ID, chatID: upd.Chat.ID, msgID: upd.MessageID}
+	mirrorMutex.Lock()
+	pending, ok := pendingMirror[key]
+	if ok {
+		pending.timer.Stop()
+		delete(pendingMirror, key)
+	}
+	mirrorMutex.Unlock()
+	if !ok {
+		return
+	}
+
+	log.Printf(ColorBlue+"🪞 Mirroring %s from @%s on msg %d in chat %d"+ColorReset, emoji, upd.User.UserName, upd.MessageID, upd.Chat.ID)
+	sendReaction(bot, upd.Chat.ID, upd.MessageID, pending.senderID, emoji)
+}
+
+// ─── /mode Command ───────────────────────
+func handleModeCommand(bot *tgbotapi.BotAPI, msg *tgbotapi.Message) {
+	arg := strings.ToLower(strings.TrimSpace(msg.CommandArguments()))
+	lang := langFor(msg)
+
+	var text string
+	switch arg {
+	case "":
+		text = T(lang, "mode.current", getReactionMode(msg.Chat.ID))
+	case ModeNormal, ModeMirror:
+		if !requireAdmin(bot, msg) {
+			return
+		}
+		setReactionMode(msg.Chat.ID, arg)
+		text = T(lang, "mode.set", arg)
+		if arg == ModeMirror {
+			text += T(lang, "mode.mirror_hint", mirrorTimeout)
+			if isGroup(msg.Chat) {
+				text += T(lang, "mode.need_admin")
+			}
+		}
+	default:
+		text = T(lang, "mode.unknown")
+	}
+
+	cfg := tgbotapi.NewMessage(msg.Chat.ID, text)
+	cfg.ParseMode = "HTML"
+	if _, err := bot.Send(cfg); err != nil {
+		logError("modeCommand", bot.Self.UserName, err)
+	}
+}
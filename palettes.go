@@ -0,0 +1,177 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"math/rand"
+	"time"
+)
+
+// ─── Palettes ────────────────────────────
+// palette is a named preset of emojis the bot picks from. Keywords are
+// what inline queries match besides the name.
+type palette struct {
+	Name     string
+	Label    string
+	Emojis   []string
+	Keywords []string
+}
+
+// palettes lists the presets in panel order. "classic" uses the full
+// emojis list and is the default.
+var palettes = []palette{
+	{Name: "classic", Label: "🎲 Classic", Emojis: emojis, Keywords: []string{"random", "all", "mix"}},
+	{Name: "love", Label: "💞 Love", Emojis: []string{"❤️", "🥰", "😍", "💘", "😘", "❤️‍🔥", "💋", "🤗"}, Keywords: []string{"heart", "kiss", "romance", "cute"}},
+	{Name: "hype", Label: "🔥 Hype", Emojis: []string{"🔥", "🎉", "🤩", "💯", "⚡", "🏆", "👏", "🍾", "😎"}, Keywords: []string{"party", "fire", "celebrate", "win", "congrats"}},
+	{Name: "funny", Label: "🤣 Funny", Emojis: []string{"😁", "🤣", "🤡", "🤪", "🙈", "🗿", "🌚", "👻"}, Keywords: []string{"lol", "haha", "joke", "meme"}},
+	{Name: "chill", Label: "🕊️ Chill", Emojis: []string{"👍", "👌", "🤝", "🫡", "🕊️", "😇", "🆒", "🐳"}, Keywords: []string{"ok", "calm", "peace", "thanks"}},
+}
+
+// seasonalThemes are extra emoji sets for the inline picker. A theme is
+// in season during its months and is then offered first.
+var seasonalThemes = []palette{
+	{Name: "winter", Label: "❄️ Winter", Emojis: []string{"❄️", "☃️", "⛄", "🧣", "🧤", "☕", "🏂", "🌨️"}, Keywords: []string{"snow", "cold"}},
+	{Name: "spring", Label: "🌸 Spring", Emojis: []string{"🌸", "🌷", "🌱", "🐝", "🦋", "🌼", "🐣", "🌈"}, Keywords: []string{"flowers", "bloom", "sakura"}},
+	{Name: "summer", Label: "☀️ Summer", Emojis: []string{"☀️", "🏖️", "🌊", "🍉", "🍦", "🕶️", "🌴", "🍹"}, Keywords: []string{"beach", "sun", "vacation"}},
+	{Name: "autumn", Label: "🍂 Autumn", Emojis: []string{"🍂", "🍁", "🎃", "🍄", "🌰", "☔", "🧥", "🍎"}, Keywords: []string{"fall", "leaves"}},
+	{Name: "halloween", Label: "🎃 Halloween", Emojis: []string{"🎃", "👻", "🦇", "🕷️", "🕸️", "💀", "🧙", "🍬"}, Keywords: []string{"spooky", "scary", "boo"}},
+	{Name: "christmas", Label: "🎄 Christmas", Emojis: []string{"🎄", "🎅", "🎁", "⭐", "🦌", "🔔", "🍪", "✨"}, Keywords: []string{"xmas", "santa", "gifts", "newyear"}},
+	{Name: "valentine", Label: "💝 Valentine", Emojis: []string{"💝", "💌", "🌹", "💕", "🍫", "💐", "😘", "💑"}, Keywords: []string{"valentines", "date"}},
+}
+
+var themeMonths = map[string][]time.Month{
+	"winter":    {time.December, time.January, time.February},
+	"spring":    {time.March, time.April, time.May},
+	"summer":    {time.June, time.July, time.August},
+	"autumn":    {time.September, time.October, time.November},
+	"halloween": {time.October},
+	"christmas": {time.December},
+	"valentine": {time.February},
+}
+
+// inSeason returns the seasonal themes for t's month.
+func inSeason(t time.Time) []palette {
+	var out []palette
+	for _, theme := range seasonalThemes {
+		for _, m := range themeMonths[theme.Name] {
+			if m == t.Month() {
+				out = append(out, theme)
+				break
+			}
+		}
+	}
+	return out
+}
+
+// rateSteps and quietSteps are the values the settings panel cycles through.
+var (
+	rateSteps  = []int{100, 75, 50, 25, 10}
+	quietSteps = []quietHours{{}, {Start: 22, End: 8}, {Start: 0, End: 7}, {Start: 23, End: 10}}
+)
+
+// quietHours is a daily UTC window without reactions. Start == End means off.
+type quietHours struct {
+	Start int
+	End   int
+}
+
+func (q quietHours) Enabled() bool { return q.Start != q.End }
+
+func (q quietHours) String() string {
+	if !q.Enabled() {
+		return "off"
+	}
+	return fmt.Sprintf("%02d:00-%02d:00 UTC", q.Start, q.End)
+}
+
+// Contains reports whether t falls inside the window, which may wrap
+// around midnight.
+func (q quietHours) Contains(t time.Time) bool {
+	if !q.Enabled() {
+		return false
+	}
+	h := t.UTC().Hour()
+	if q.Start < q.End {
+		return h >= q.Start && h < q.End
+	}
+	return h >= q.Start || h < q.End
+}
+
+var (
+	chatPalettes = make(map[int64]string)     // chatID -> palette name, guarded by reactionMutex
+	chatRates    = make(map[int64]int)        // chatID -> reaction chance in percent
+	chatQuiet    = make(map[int64]quietHours) // chatID -> quiet hours window
+)
+
+func findPalette(name string) (palette, bool) {
+	for _, p := range palettes {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	return palette{}, false
+}
+
+func getPalette(chatID int64) palette {
+	reactionMutex.RLock()
+	name := chatPalettes[chatID]
+	reactionMutex.RUnlock()
+	if p, ok := findPalette(name); ok {
+		return p
+	}
+	return palettes[0]
+}
+
+func setPalette(chatID int64, name string) {
+	reactionMutex.Lock()
+	defer reactionMutex.Unlock()
+	chatPalettes[chatID] = name
+	log.Printf(ColorBlue+"🎨 Palette %s for chat %d"+ColorReset, name, chatID)
+}
+
+func getRate(chatID int64) int {
+	reactionMutex.RLock()
+	defer reactionMutex.RUnlock()
+	if rate, ok := chatRates[chatID]; ok {
+		return rate
+	}
+	return 100
+}
+
+func setRate(chatID int64, rate int) {
+	reactionMutex.Lock()
+	defer reactionMutex.Unlock()
+	chatRates[chatID] = rate
+	log.Printf(ColorBlue+"🎲 Reaction rate %d%% for chat %d"+ColorReset, rate, chatID)
+}
+
+func getQuietHours(chatID int64) quietHours {
+	reactionMutex.RLock()
+	defer reactionMutex.RUnlock()
+	return chatQuiet[chatID]
+}
+
+func setQuietHours(chatID int64, q quietHours) {
+	reactionMutex.Lock()
+	defer reactionMutex.Unlock()
+	chatQuiet[chatID] = q
+	log.Printf(ColorBlue+"🌙 Quiet hours %s for chat %d"+ColorReset, q, chatID)
+}
+
+// pickEmoji returns a random emoji from the chat's palette.
+func pickEmoji(chatID int64) string {
+	p := getPalette(chatID)
+	return p.Emojis[rand.Intn(len(p.Emojis))]
+}
+
+// pacingSkip applies quiet hours and the reaction rate. It returns the
+// reason for skipping, or "" when the bot should react.
+func pacingSkip(chatID int64) string {
+	if getQuietHours(chatID).Contains(time.Now()) {
+		return "quiet hours"
+	}
+	if rate := getRate(chatID); rate < 100 && rand.Intn(100) >= rate {
+		return "rate"
+	}
+	return ""
+}
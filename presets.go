@@ -0,0 +1,251 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// ─── Start Presets ───────────────────────
+// A preset travels in the startgroup deep link as key_value pairs, e.g.
+// "palette_hype_rate_50". Telegram sends it back as the /start argument
+// when the bot joins the group, and it is applied right away. Payloads
+// are limited to 64 characters of [A-Za-z0-9_-].
+const presetPrefix = "pre:"
+
+type startPreset struct {
+	Palette string
+	Rate    int // 0 when not set
+	Mode    string
+	Quiet   *quietHours
+}
+
+// payload encodes p for a startgroup link.
+func (p startPreset) payload() string {
+	var parts []string
+	if p.Palette != "" {
+		parts = append(parts, "palette", p.Palette)
+	}
+	if p.Rate != 0 {
+		parts = append(parts, "rate", strconv.Itoa(p.Rate))
+	}
+	if p.Mode != "" {
+		parts = append(parts, "mode", p.Mode)
+	}
+	if p.Quiet != nil {
+		q := "off"
+		if p.Quiet.Enabled() {
+			q = fmt.Sprintf("%d-%d", p.Quiet.Start, p.Quiet.End)
+		}
+		parts = append(parts, "quiet", q)
+	}
+	return strings.Join(parts, "_")
+}
+
+// String describes the preset for the confirmation message.
+func (p startPreset) String() string {
+	var parts []string
+	if pal, ok := findPalette(p.Palette); ok {
+		parts = append(parts, pal.Label)
+	}
+	if p.Rate != 0 {
+		parts = append(parts, fmt.Sprintf("🎲 %d%%", p.Rate))
+	}
+	if p.Mode != "" {
+		parts = append(parts, "🪞 "+p.Mode)
+	}
+	if p.Quiet != nil {
+		parts = append(parts, "🌙 "+p.Quiet.String())
+	}
+	return strings.Join(parts, ", ")
+}
+
+// parseStartPayload decodes a start payload. "true" and "" are the plain
+// add-to-group link and yield an empty preset.
+func parseStartPayload(s string) (startPreset, error) {
+	var p startPreset
+	if s == "" || s == "true" {
+		return p, nil
+	}
+	parts := strings.Split(strings.ToLower(s), "_")
+	if len(parts)%2 != 0 {
+		return p, fmt.Errorf("odd number of fields in %q", s)
+	}
+	for i := 0; i < len(parts); i += 2 {
+		key, value := parts[i], parts[i+1]
+		switch key {
+		case "palette":
+			if _, ok := findPalette(value); !ok {
+				return p, fmt.Errorf("unknown palette %q", value)
+			}
+			p.Palette = value
+		case "rate":
+			rate, err := strconv.Atoi(value)
+			if err != nil || rate < 1 || rate > 100 {
+				return p, fmt.Errorf("bad rate %q", value)
+			}
+			p.Rate = rate
+		case "mode":
+			if value != ModeNormal && value != ModeMirror {
+				return p, fmt.Errorf("unknown mode %q", value)
+			}
+			p.Mode = value
+		case "quiet":
+			q, err := parseQuietHours(value)
+			if err != nil {
+				return p, err
+			}
+			p.Quiet = &q
+		default:
+			return p, fmt.Errorf("unknown key %q", key)
+		}
+	}
+	return p, nil
+}
+
+// parseQuietHours reads "22-8" or "off".
+func parseQuietHours(s string) (quietHours, error) {
+	if s == "off" {
+		return quietHours{}, nil
+	}
+	from, to, ok := strings.Cut(s, "-")
+	start, err1 := strconv.Atoi(from)
+	end, err2 := strconv.Atoi(to)
+	if !ok || err1 != nil || err2 != nil || start < 0 || start > 23 || end < 0 || end > 23 {
+		return quietHours{}, fmt.Errorf("bad quiet hours %q", s)
+	}
+	return quietHours{Start: start, End: end}, nil
+}
+
+func (p startPreset) empty() bool {
+	return p.Palette == "" && p.Rate == 0 && p.Mode == "" && p.Quiet == nil
+}
+
+func (p startPreset) apply(chatID int64) {
+	if p.Palette != "" {
+		setPalette(chatID, p.Palette)
+	}
+	if p.Rate != 0 {
+		setRate(chatID, p.Rate)
+	}
+	if p.Mode != "" {
+		setReactionMode(chatID, p.Mode)
+	}
+	if p.Quiet != nil {
+		setQuietHours(chatID, *p.Quiet)
+	}
+}
+
+// applyStartPreset applies the preset in a group /start. Only admins may
+// change settings this way; anyone else gets the plain welcome.
+func applyStartPreset(bot *tgbotapi.BotAPI, msg *tgbotapi.Message) {
+	preset, err := parseStartPayload(msg.CommandArguments())
+	if err != nil {
+		log.Printf(ColorYellow+"⚠️  Ignoring start payload in %d: %v"+ColorReset, msg.Chat.ID, err)
+		return
+	}
+	if preset.empty() {
+		return
+	}
+	if !isChatAdmin(bot, msg) {
+		log.Printf(ColorYellow+"⚠️  Ignoring start preset from non-admin @%s in %d"+ColorReset, msg.From.UserName, msg.Chat.ID)
+		return
+	}
+
+	preset.apply(msg.Chat.ID)
+	log.Printf(ColorGreen+"🎁 Start preset %q applied in %d"+ColorReset, preset.payload(), msg.Chat.ID)
+	cfg := tgbotapi.NewMessage(msg.Chat.ID, T(langFor(msg), "preset.applied", preset))
+	if _, err := bot.Send(cfg); err != nil {
+		logError("startPreset", bot.Self.UserName, err)
+	}
+}
+
+// ─── Preset Picker ───────────────────────
+// The private welcome offers a two-step picker: a palette, then a rate.
+// The last step is a startgroup link carrying both.
+func addGroupURL(bot *tgbotapi.BotAPI, p startPreset) string {
+	payload := p.payload()
+	if payload == "" {
+		payload = "true"
+	}
+	return fmt.Sprintf("https://t.me/%s?startgroup=%s", bot.Self.UserName, payload)
+}
+
+func welcomeKeyboard(bot *tgbotapi.BotAPI, lang string) tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonURL(T(lang, "btn.updates"), channelURL),
+			tgbotapi.NewInlineKeyboardButtonURL(T(lang, "btn.support"), groupURL),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonURL(T(lang, "btn.add_group"), addGroupURL(bot, startPreset{})),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(T(lang, "btn.preset"), presetPrefix+"palettes"),
+		),
+	)
+}
+
+func presetPaletteKeyboard(lang string) tgbotapi.InlineKeyboardMarkup {
+	var rows [][]tgbotapi.InlineKeyboardButton
+	for _, p := range palettes {
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(p.Label, presetPrefix+"palette:"+p.Name),
+		))
+	}
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(T(lang, "btn.back"), presetPrefix+"main"),
+	))
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+func presetRateKeyboard(bot *tgbotapi.BotAPI, lang, paletteName string) tgbotapi.InlineKeyboardMarkup {
+	var row []tgbotapi.InlineKeyboardButton
+	for _, rate := range rateSteps {
+		url := addGroupURL(bot, startPreset{Palette: paletteName, Rate: rate})
+		row = append(row, tgbotapi.NewInlineKeyboardButtonURL(fmt.Sprintf("➕ %d%%", rate), url))
+	}
+	return tgbotapi.NewInlineKeyboardMarkup(
+		row,
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(T(lang, "btn.back"), presetPrefix+"palettes"),
+		),
+	)
+}
+
+func handlePresetCallback(bot *tgbotapi.BotAPI, cb *tgbotapi.CallbackQuery) {
+	msg := cb.Message
+	lang := langFor(&tgbotapi.Message{Chat: msg.Chat, From: cb.From})
+
+	action := strings.TrimPrefix(cb.Data, presetPrefix)
+	value := ""
+	if i := strings.Index(action, ":"); i >= 0 {
+		action, value = action[:i], action[i+1:]
+	}
+
+	var kb tgbotapi.InlineKeyboardMarkup
+	switch action {
+	case "palettes":
+		kb = presetPaletteKeyboard(lang)
+	case "palette":
+		if _, ok := findPalette(value); !ok {
+			answerCallback(bot, cb.ID, T(lang, "preset.unknown_palette"))
+			return
+		}
+		kb = presetRateKeyboard(bot, lang, value)
+	case "main":
+		kb = welcomeKeyboard(bot, lang)
+	default:
+		answerCallback(bot, cb.ID, T(lang, "settings.unknown"))
+		return
+	}
+
+	answerCallback(bot, cb.ID, "")
+	edit := tgbotapi.NewEditMessageReplyMarkup(msg.Chat.ID, msg.MessageID, kb)
+	if _, err := bot.Send(edit); err != nil {
+		logError("presetPicker", bot.Self.UserName, err)
+	}
+}
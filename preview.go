@@ -0,0 +1,138 @@
+package main
+
+import (
+	"log"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// ─── Broadcast Preview ───────────────────
+// A broadcast payload is not sent right away: the bot copies it back to
+// the owner with buttons to send it, send it silently, toggle pinning or
+// cancel. Pending previews live in memory only; after a restart the owner
+// simply sends the message again.
+const broadcastPrefix = "bc:"
+
+type previewKey struct {
+	BotID     int64
+	MessageID int // the preview copy in the owner's chat
+}
+
+type pendingBroadcast struct {
+	Msg   *tgbotapi.Message
+	Draft *broadcastDraft
+	Pin   bool
+}
+
+var pendingBroadcasts = make(map[previewKey]*pendingBroadcast) // guarded by mutex
+
+// previewBroadcast shows msg to the owner and waits for a button.
+func previewBroadcast(bot *tgbotapi.BotAPI, msg *tgbotapi.Message, draft *broadcastDraft) {
+	lang := langFor(msg)
+	matched, err := draft.Audience.recipients()
+	if err != nil {
+		logError("broadcastAudience", bot.Self.UserName, err)
+		return
+	}
+
+	text := T(lang, "broadcast.preview", draft.Audience, len(matched))
+	if draft.Schedule != nil {
+		text += "\n\n🗓️ `" + draft.Schedule.Spec + "`"
+	}
+	header := tgbotapi.NewMessage(msg.Chat.ID, text)
+	header.ParseMode = "Markdown"
+	if _, err := bot.Send(header); err != nil {
+		logError("broadcastPreview", bot.Self.UserName, err)
+	}
+
+	copyCfg := tgbotapi.NewCopyMessage(msg.Chat.ID, msg.Chat.ID, msg.MessageID)
+	copyCfg.ReplyMarkup = previewKeyboard(lang, false)
+	copied, err := bot.Send(copyCfg)
+	if err != nil {
+		logError("broadcastPreview", bot.Self.UserName, err)
+		return
+	}
+
+	mutex.Lock()
+	pendingBroadcasts[previewKey{bot.Self.ID, copied.MessageID}] = &pendingBroadcast{Msg: msg, Draft: draft}
+	mutex.Unlock()
+	log.Printf(ColorBlue+"👀 Broadcast preview sent to @%s via bot %s (%d chats)"+ColorReset,
+		msg.From.UserName, bot.Self.UserName, len(matched))
+}
+
+func previewKeyboard(lang string, pin bool) tgbotapi.InlineKeyboardMarkup {
+	state := T(lang, "broadcast.pin_off")
+	if pin {
+		state = T(lang, "broadcast.pin_on")
+	}
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(T(lang, "broadcast.btn_send"), broadcastPrefix+"send"),
+			tgbotapi.NewInlineKeyboardButtonData(T(lang, "broadcast.btn_silent"), broadcastPrefix+"silent"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(T(lang, "broadcast.btn_pin", state), broadcastPrefix+"pin"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(T(lang, "broadcast.btn_cancel"), broadcastPrefix+"cancel"),
+		),
+	)
+}
+
+func handleBroadcastCallback(bot *tgbotapi.BotAPI, cb *tgbotapi.CallbackQuery) {
+	lang := langFor(&tgbotapi.Message{Chat: cb.Message.Chat, From: cb.From})
+	if cb.From.ID != ownerID {
+		answerCallback(bot, cb.ID, T(lang, "error.owner_only"))
+		return
+	}
+	key := previewKey{bot.Self.ID, cb.Message.MessageID}
+	action := strings.TrimPrefix(cb.Data, broadcastPrefix)
+
+	mutex.Lock()
+	p := pendingBroadcasts[key]
+	switch {
+	case p == nil:
+	case action == "pin":
+		p.Pin = !p.Pin
+	default:
+		delete(pendingBroadcasts, key)
+	}
+	mutex.Unlock()
+
+	if p == nil {
+		answerCallback(bot, cb.ID, T(lang, "broadcast.expired"))
+		clearPreviewButtons(bot, cb.Message)
+		return
+	}
+
+	opts := broadcastOptions{Silent: action == "silent", Pin: p.Pin}
+	switch action {
+	case "pin":
+		answerCallback(bot, cb.ID, "")
+		edit := tgbotapi.NewEditMessageReplyMarkup(cb.Message.Chat.ID, cb.Message.MessageID, previewKeyboard(lang, opts.Pin))
+		if _, err := bot.Send(edit); err != nil {
+			logError("broadcastPreview", bot.Self.UserName, err)
+		}
+	case "send", "silent":
+		answerCallback(bot, cb.ID, T(lang, "broadcast.starting"))
+		clearPreviewButtons(bot, cb.Message)
+		if p.Draft.Schedule != nil {
+			queueScheduled(bot, p.Msg, p.Draft, opts)
+		} else {
+			startBroadcast(bot, p.Msg, p.Draft, opts)
+		}
+	default:
+		answerCallback(bot, cb.ID, T(lang, "broadcast.cancelled"))
+		clearPreviewButtons(bot, cb.Message)
+		log.Printf(ColorBlue+"🛑 Broadcast preview cancelled by @%s via bot %s"+ColorReset, cb.From.UserName, bot.Self.UserName)
+	}
+}
+
+func clearPreviewButtons(bot *tgbotapi.BotAPI, msg *tgbotapi.Message) {
+	none := tgbotapi.InlineKeyboardMarkup{InlineKeyboard: [][]tgbotapi.InlineKeyboardButton{}}
+	edit := tgbotapi.NewEditMessageReplyMarkup(msg.Chat.ID, msg.MessageID, none)
+	if _, err := bot.Send(edit); err != nil {
+		logError("broadcastPreview", bot.Self.UserName, err)
+	}
+}
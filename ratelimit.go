@@ -0,0 +1,77 @@
+package main
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// ─── Send Rate Limits ────────────────────
+// Telegram allows a bot about 30 messages per second overall. Bulk sends
+// take a slot from the bot's limiter first; a 429 pushes the whole bot
+// back by the retry_after Telegram asks for.
+const (
+	globalSendRate = 30
+	maxSendRetries = 3
+)
+
+type rateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time // earliest start of the next send
+}
+
+var (
+	limiters     = make(map[int64]*rateLimiter) // botID -> limiter
+	limiterMutex sync.Mutex                     // protects limiters
+)
+
+func limiterFor(botID int64) *rateLimiter {
+	limiterMutex.Lock()
+	defer limiterMutex.Unlock()
+	l, ok := limiters[botID]
+	if !ok {
+		l = &rateLimiter{interval: time.Second / globalSendRate}
+		limiters[botID] = l
+	}
+	return l
+}
+
+// wait blocks until the caller may send.
+func (l *rateLimiter) wait() {
+	l.mu.Lock()
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	at := l.next
+	l.next = l.next.Add(l.interval)
+	l.mu.Unlock()
+	time.Sleep(time.Until(at))
+}
+
+// pause holds back every send for d.
+func (l *rateLimiter) pause(d time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if until := time.Now().Add(d); until.After(l.next) {
+		l.next = until
+	}
+}
+
+// sendLimited sends c through bot's limiter, retrying when Telegram says
+// to slow down.
+func sendLimited(bot *tgbotapi.BotAPI, c tgbotapi.Chattable) (tgbotapi.Message, error) {
+	l := limiterFor(bot.Self.ID)
+	for attempt := 0; ; attempt++ {
+		l.wait()
+		sent, err := bot.Send(c)
+		var tgErr *tgbotapi.Error
+		if err == nil || attempt == maxSendRetries || !errors.As(err, &tgErr) || tgErr.RetryAfter == 0 {
+			return sent, err
+		}
+		l.pause(time.Duration(tgErr.RetryAfter) * time.Second)
+	}
+}
@@ -0,0 +1,379 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// ─── Scheduled Broadcasts ────────────────
+// In broadcast mode "/schedule <when>" queues the next message instead of
+// sending it. All times are UTC:
+//
+//	in 30m | in 2h | in 3d      once, after a delay
+//	18:30                       once, at the next 18:30
+//	2026-12-24 18:00            once, at that time
+//	daily 09:00                 every day
+//	weekly mon 09:00            every week
+//	every 6h                    repeatedly, at least every 10m
+//
+// The audience filters are stored as given and matched again on every run.
+// Each row keeps its next run time and whether it recurs; only recurring
+// specs are parsed again to find the run after that.
+const (
+	schedulerTick = 30 * time.Second
+	minInterval   = 10 * time.Minute
+)
+
+type schedule struct {
+	Spec    string
+	Once    time.Time     // one-shot time, zero for recurring schedules
+	Every   time.Duration // fixed interval
+	Daily   bool
+	Weekday time.Weekday
+	Weekly  bool
+	Hour    int
+	Minute  int
+}
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+func parseSchedule(spec string, now time.Time) (schedule, error) {
+	spec = strings.Join(strings.Fields(strings.ToLower(spec)), " ")
+	s := schedule{Spec: spec}
+	fields := strings.Fields(spec)
+	now = now.UTC()
+
+	switch {
+	case len(fields) == 2 && fields[0] == "in":
+		d, err := parseWindow(fields[1])
+		if err != nil {
+			return s, err
+		}
+		s.Once = now.Add(d)
+	case len(fields) == 2 && fields[0] == "every":
+		d, err := parseWindow(fields[1])
+		if err != nil {
+			return s, err
+		}
+		if d < minInterval {
+			return s, fmt.Errorf("interval must be at least %s", minInterval)
+		}
+		s.Every = d
+	case len(fields) == 2 && fields[0] == "daily":
+		s.Daily = true
+		return s, s.parseClock(fields[1])
+	case len(fields) == 3 && fields[0] == "weekly":
+		day, ok := weekdays[fields[1]]
+		if !ok {
+			return s, fmt.Errorf("unknown weekday %q", fields[1])
+		}
+		s.Weekly, s.Weekday = true, day
+		return s, s.parseClock(fields[2])
+	case len(fields) == 1:
+		if err := s.parseClock(fields[0]); err != nil {
+			return s, err
+		}
+		s.Once = s.nextClock(now)
+	case len(fields) == 2:
+		t, err := time.Parse("2006-01-02 15:04", spec)
+		if err != nil {
+			return s, fmt.Errorf("bad date %q, use YYYY-MM-DD HH:MM", spec)
+		}
+		if !t.After(now) {
+			return s, fmt.Errorf("%s is in the past", spec)
+		}
+		s.Once = t
+	default:
+		return s, fmt.Errorf("can't read %q", spec)
+	}
+	return s, nil
+}
+
+func (s *schedule) parseClock(clock string) error {
+	t, err := time.Parse("15:04", clock)
+	if err != nil {
+		return fmt.Errorf("bad time %q, use HH:MM", clock)
+	}
+	s.Hour, s.Minute = t.Hour(), t.Minute()
+	return nil
+}
+
+// nextClock returns the first HH:MM after now, on the right weekday for
+// weekly schedules.
+func (s schedule) nextClock(now time.Time) time.Time {
+	t := time.Date(now.Year(), now.Month(), now.Day(), s.Hour, s.Minute, 0, 0, time.UTC)
+	for !t.After(now) || (s.Weekly && t.Weekday() != s.Weekday) {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t
+}
+
+// Recurring reports whether the schedule runs more than once.
+func (s schedule) Recurring() bool { return s.Once.IsZero() }
+
+// Next returns the first run after now, or zero when a one-shot schedule
+// has run.
+func (s schedule) Next(now time.Time) time.Time {
+	now = now.UTC()
+	switch {
+	case !s.Once.IsZero():
+		if s.Once.After(now) {
+			return s.Once
+		}
+		return time.Time{}
+	case s.Every > 0:
+		return now.Add(s.Every)
+	default:
+		return s.nextClock(now)
+	}
+}
+
+// ─── Schedule Store ──────────────────────
+func storeScheduled(origin *tgbotapi.BotAPI, msg *tgbotapi.Message, lang, filters string, s schedule, first time.Time, opts broadcastOptions) (int64, error) {
+	entities, err := json.Marshal(msg.Entities)
+	if err != nil {
+		return 0, fmt.Errorf("encode entities: %w", err)
+	}
+	res, err := db.Exec(`INSERT INTO scheduled_broadcasts
+		(origin_bot_id, from_chat_id, message_id, text, entities, lang, filters, spec, next_run, recurring, silent, pin)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		origin.Self.ID, msg.Chat.ID, msg.MessageID, msg.Text, string(entities), lang, filters, s.Spec, first.Unix(),
+		s.Recurring(), opts.Silent, opts.Pin)
+	if err != nil {
+		return 0, fmt.Errorf("insert scheduled broadcast: %w", err)
+	}
+	return res.LastInsertId()
+}
+
+type scheduledBroadcast struct {
+	ID        int64
+	BotID     int64
+	Msg       *tgbotapi.Message
+	Lang      string
+	Filters   string
+	Spec      string
+	NextRun   time.Time
+	Recurring bool
+	Opts      broadcastOptions
+}
+
+func loadScheduled(query string, args ...interface{}) ([]scheduledBroadcast, error) {
+	rows, err := db.Query(`SELECT id, origin_bot_id, from_chat_id, message_id, text, entities, lang, filters, spec, next_run, recurring, silent, pin
+		FROM scheduled_broadcasts `+query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query scheduled broadcasts: %w", err)
+	}
+	defer rows.Close()
+
+	var out []scheduledBroadcast
+	for rows.Next() {
+		var (
+			sb       = scheduledBroadcast{Msg: &tgbotapi.Message{}}
+			fromChat int64
+			entities string
+			nextRun  int64
+		)
+		if err := rows.Scan(&sb.ID, &sb.BotID, &fromChat, &sb.Msg.MessageID, &sb.Msg.Text, &entities,
+			&sb.Lang, &sb.Filters, &sb.Spec, &nextRun, &sb.Recurring, &sb.Opts.Silent, &sb.Opts.Pin); err != nil {
+			return nil, fmt.Errorf("scan scheduled broadcast: %w", err)
+		}
+		sb.Msg.Chat = &tgbotapi.Chat{ID: fromChat}
+		if err := json.Unmarshal([]byte(entities), &sb.Msg.Entities); err != nil {
+			return nil, fmt.Errorf("decode entities of schedule %d: %w", sb.ID, err)
+		}
+		sb.NextRun = time.Unix(nextRun, 0).UTC()
+		out = append(out, sb)
+	}
+	return out, rows.Err()
+}
+
+// ─── Scheduler ───────────────────────────
+// runScheduler starts due broadcasts until ctx is done. A schedule whose
+// bot is not running is left for a later tick.
+func runScheduler(ctx context.Context) {
+	ticker := time.NewTicker(schedulerTick)
+	defer ticker.Stop()
+	log.Println(ColorCyan + "🗓️  Broadcast scheduler started" + ColorReset)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			due, err := loadScheduled(`WHERE active = 1 AND next_run <= ? ORDER BY next_run`, now.Unix())
+			if err != nil {
+				logError("scheduler", "", err)
+				continue
+			}
+			for _, sb := range due {
+				runScheduled(sb, now)
+			}
+		}
+	}
+}
+
+func runScheduled(sb scheduledBroadcast, now time.Time) {
+	var origin *tgbotapi.BotAPI
+	for _, bot := range currentBots() {
+		if bot.Self.ID == sb.BotID {
+			origin = bot
+		}
+	}
+	if origin == nil {
+		return
+	}
+
+	// Move the schedule on first, so a crash never runs it twice
+	var next time.Time
+	if sb.Recurring {
+		s, err := parseSchedule(sb.Spec, now)
+		if err != nil {
+			logError("scheduler spec", fmt.Sprint(sb.ID), err)
+		} else {
+			next = s.Next(now)
+		}
+	}
+	var err error
+	if next.IsZero() {
+		_, err = db.Exec(`UPDATE scheduled_broadcasts SET active = 0 WHERE id = ?`, sb.ID)
+	} else {
+		_, err = db.Exec(`UPDATE scheduled_broadcasts SET next_run = ? WHERE id = ?`, next.Unix(), sb.ID)
+	}
+	if err != nil {
+		logError("scheduler", fmt.Sprint(sb.ID), err)
+		return
+	}
+
+	aud, err := parseAudience(sb.Filters)
+	if err != nil {
+		logError("scheduler audience", fmt.Sprint(sb.ID), err)
+		return
+	}
+	recipients, err := aud.recipients()
+	if err != nil {
+		logError("scheduler audience", fmt.Sprint(sb.ID), err)
+		return
+	}
+	job, err := createBroadcastJob(origin, sb.Msg, sb.Lang, recipients, aud.Bot, sb.Opts)
+	if err != nil {
+		logError("scheduler job", fmt.Sprint(sb.ID), err)
+		return
+	}
+	log.Printf(ColorBlue+"🗓️  Scheduled broadcast #%d started as job #%d"+ColorReset, sb.ID, job.id)
+	go job.run()
+}
+
+// ─── Schedule Commands ───────────────────
+// handleScheduleCommand sets when the next broadcast goes out. It only
+// works in broadcast mode.
+func handleScheduleCommand(c *commandContext) {
+	mutex.Lock()
+	draft := broadcastDrafts[ownerID]
+	armed := broadcastMap[ownerID]
+	mutex.Unlock()
+
+	var text string
+	s, err := parseSchedule(c.Msg.CommandArguments(), time.Now())
+	switch {
+	case !armed || draft == nil:
+		text = T(c.Lang, "schedule.not_armed")
+	case c.Msg.CommandArguments() == "":
+		text = T(c.Lang, "schedule.usage")
+	case err != nil:
+		text = "❌ " + err.Error()
+	default:
+		mutex.Lock()
+		draft.Schedule = &s
+		mutex.Unlock()
+		text = T(c.Lang, "schedule.set", s.Spec, s.Next(time.Now()).Format("2006-01-02 15:04"))
+	}
+	if _, err := c.Bot.Send(tgbotapi.NewMessage(c.Msg.Chat.ID, text)); err != nil {
+		logError("scheduleCommand", c.Bot.Self.UserName, err)
+	}
+}
+
+// queueScheduled stores msg for its draft's schedule instead of sending.
+func queueScheduled(bot *tgbotapi.BotAPI, msg *tgbotapi.Message, draft *broadcastDraft, opts broadcastOptions) {
+	lang := langFor(msg)
+	// A one-shot time can pass while the preview waits for confirmation
+	first := draft.Schedule.Next(time.Now())
+	if first.IsZero() {
+		if _, err := bot.Send(tgbotapi.NewMessage(msg.Chat.ID, T(lang, "schedule.passed", draft.Schedule.Spec))); err != nil {
+			logError("scheduleConfirm", bot.Self.UserName, err)
+		}
+		return
+	}
+	id, err := storeScheduled(bot, msg, lang, draft.Filters, *draft.Schedule, first, opts)
+	text := T(lang, "schedule.queued", id, draft.Schedule.Spec, first.Format("2006-01-02 15:04"))
+	if err != nil {
+		logError("storeScheduled", bot.Self.UserName, err)
+		text = T(lang, "schedule.failed")
+	} else {
+		log.Printf(ColorBlue+"🗓️  Broadcast scheduled #%d: %s"+ColorReset, id, draft.Schedule.Spec)
+	}
+	if _, err := bot.Send(tgbotapi.NewMessage(msg.Chat.ID, text)); err != nil {
+		logError("scheduleConfirm", bot.Self.UserName, err)
+	}
+}
+
+func handleScheduledCommand(c *commandContext) {
+	list, err := loadScheduled(`WHERE active = 1 ORDER BY next_run`)
+	if err != nil {
+		logError("scheduledCommand", c.Bot.Self.UserName, err)
+		return
+	}
+
+	var b strings.Builder
+	b.WriteString(T(c.Lang, "scheduled.title") + "\n\n")
+	for _, sb := range list {
+		filters := sb.Filters
+		if filters == "" {
+			filters = "everyone"
+		}
+		b.WriteString(T(c.Lang, "scheduled.item",
+			sb.ID, sb.NextRun.Format("2006-01-02 15:04"), html.EscapeString(sb.Spec),
+			html.EscapeString(filters), html.EscapeString(preview(sb.Msg.Text))) + "\n")
+	}
+	if len(list) == 0 {
+		b.WriteString(T(c.Lang, "scheduled.empty"))
+	} else {
+		b.WriteString("\n" + T(c.Lang, "scheduled.hint"))
+	}
+
+	cfg := tgbotapi.NewMessage(c.Msg.Chat.ID, b.String())
+	cfg.ParseMode = "HTML"
+	if _, err := c.Bot.Send(cfg); err != nil {
+		logError("scheduledCommand", c.Bot.Self.UserName, err)
+	}
+}
+
+func handleUnscheduleCommand(c *commandContext) {
+	text := T(c.Lang, "unschedule.usage")
+	if id, err := strconv.ParseInt(strings.TrimPrefix(c.Msg.CommandArguments(), "#"), 10, 64); err == nil {
+		res, err := db.Exec(`UPDATE scheduled_broadcasts SET active = 0 WHERE id = ? AND active = 1`, id)
+		if err != nil {
+			logError("unschedule", c.Bot.Self.UserName, err)
+			return
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			text = T(c.Lang, "unschedule.missing", id)
+		} else {
+			log.Printf(ColorBlue+"🗓️  Schedule #%d cancelled"+ColorReset, id)
+			text = T(c.Lang, "unschedule.done", id)
+		}
+	}
+	if _, err := c.Bot.Send(tgbotapi.NewMessage(c.Msg.Chat.ID, text)); err != nil {
+		logError("unschedule", c.Bot.Self.UserName, err)
+	}
+}
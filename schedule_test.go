@@ -0,0 +1,131 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func at(s string) time.Time {
+	t, err := time.Parse("2006-01-02 15:04", s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestParseSchedule(t *testing.T) {
+	now := at("2026-10-19 23:50") // a Monday
+
+	tests := []struct {
+		spec      string
+		once      time.Time // zero for recurring schedules
+		recurring bool
+		wantErr   bool
+	}{
+		{spec: "in 30m", once: at("2026-10-20 00:20")},
+		{spec: "in 2d", once: at("2026-10-21 23:50")},
+		{spec: "00:10", once: at("2026-10-20 00:10")},
+		{spec: "23:50", once: at("2026-10-20 23:50")},
+		{spec: "23:55", once: at("2026-10-19 23:55")},
+		{spec: "2026-12-24 18:00", once: at("2026-12-24 18:00")},
+		{spec: "  2026-12-24   18:00 ", once: at("2026-12-24 18:00")},
+		{spec: "2026-10-19 23:50", wantErr: true},
+		{spec: "2026-10-19 12:00", wantErr: true},
+		{spec: "2026-13-01 10:00", wantErr: true},
+		{spec: "daily 09:00", recurring: true},
+		{spec: "weekly mon 09:00", recurring: true},
+		{spec: "every 6h", recurring: true},
+		{spec: "every 10m", recurring: true},
+		{spec: "every 5m", wantErr: true},
+		{spec: "in 0m", wantErr: true},
+		{spec: "daily 25:00", wantErr: true},
+		{spec: "weekly xyz 09:00", wantErr: true},
+		{spec: "", wantErr: true},
+		{spec: "tomorrow", wantErr: true},
+	}
+	for _, tt := range tests {
+		s, err := parseSchedule(tt.spec, now)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("parseSchedule(%q) = %+v, want error", tt.spec, s)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseSchedule(%q): %v", tt.spec, err)
+			continue
+		}
+		if !s.Once.Equal(tt.once) {
+			t.Errorf("parseSchedule(%q).Once = %s, want %s", tt.spec, s.Once, tt.once)
+		}
+		if s.Recurring() != tt.recurring {
+			t.Errorf("parseSchedule(%q).Recurring() = %v, want %v", tt.spec, s.Recurring(), tt.recurring)
+		}
+	}
+}
+
+func TestNextClock(t *testing.T) {
+	tests := []struct {
+		spec string
+		now  string
+		want string
+	}{
+		{"daily 09:00", "2026-10-19 08:59", "2026-10-19 09:00"},
+		{"daily 09:00", "2026-10-19 09:00", "2026-10-20 09:00"},
+		{"daily 00:00", "2026-10-19 23:59", "2026-10-20 00:00"},
+		{"daily 23:59", "2026-10-20 00:00", "2026-10-20 23:59"},
+		{"daily 09:00", "2026-10-31 10:00", "2026-11-01 09:00"},
+		{"daily 09:00", "2026-12-31 10:00", "2027-01-01 09:00"},
+		{"weekly mon 09:00", "2026-10-19 08:00", "2026-10-19 09:00"},
+		{"weekly mon 09:00", "2026-10-19 09:00", "2026-10-26 09:00"},
+		{"weekly mon 09:00", "2026-10-19 10:00", "2026-10-26 09:00"},
+		{"weekly sun 00:00", "2026-10-24 23:59", "2026-10-25 00:00"},
+		{"weekly sat 23:30", "2026-10-25 00:00", "2026-10-31 23:30"},
+		{"weekly fri 12:00", "2026-12-31 12:00", "2027-01-01 12:00"},
+	}
+	for _, tt := range tests {
+		s, err := parseSchedule(tt.spec, at(tt.now))
+		if err != nil {
+			t.Fatalf("parseSchedule(%q): %v", tt.spec, err)
+		}
+		if got := s.nextClock(at(tt.now)); !got.Equal(at(tt.want)) {
+			t.Errorf("%q at %s: nextClock = %s, want %s", tt.spec, tt.now, got.Format("2006-01-02 15:04 Mon"), tt.want)
+		}
+	}
+}
+
+func TestNext(t *testing.T) {
+	created := at("2026-10-19 12:00")
+	tests := []struct {
+		spec string
+		now  string
+		want string // "" for no further run
+	}{
+		{"in 30m", "2026-10-19 12:10", "2026-10-19 12:30"},
+		{"in 30m", "2026-10-19 12:30", ""},
+		{"in 30m", "2026-10-19 13:00", ""},
+		{"2026-12-24 18:00", "2026-12-24 17:59", "2026-12-24 18:00"},
+		{"2026-12-24 18:00", "2026-12-24 18:00", ""},
+		{"18:00", "2026-10-19 17:00", "2026-10-19 18:00"},
+		{"18:00", "2026-10-20 09:00", ""},
+		{"every 6h", "2026-10-19 22:00", "2026-10-20 04:00"},
+		{"daily 09:00", "2026-10-19 23:00", "2026-10-20 09:00"},
+		{"weekly mon 09:00", "2026-10-25 23:00", "2026-10-26 09:00"},
+	}
+	for _, tt := range tests {
+		s, err := parseSchedule(tt.spec, created)
+		if err != nil {
+			t.Fatalf("parseSchedule(%q): %v", tt.spec, err)
+		}
+		got := s.Next(at(tt.now))
+		if tt.want == "" {
+			if !got.IsZero() {
+				t.Errorf("%q at %s: Next = %s, want none", tt.spec, tt.now, got)
+			}
+			continue
+		}
+		if !got.Equal(at(tt.want)) {
+			t.Errorf("%q at %s: Next = %s, want %s", tt.spec, tt.now, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"log"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// ─── Settings Panel ──────────────────────
+// Button data is "set:<action>[:<value>]" and always edits the panel
+// message the button belongs to. Opened inside a forum topic, the panel
+// shows and toggles that topic's reactions, like /begin and /end there.
+const settingsPrefix = "set:"
+
+func handleSettingsCommand(c *commandContext) {
+	msg := c.Msg
+	cfg := tgbotapi.NewMessage(msg.Chat.ID, settingsText(c.Lang, msg.Chat, c.ThreadID))
+	cfg.ParseMode = "HTML"
+	cfg.ReplyMarkup = settingsKeyboard(c.Lang, msg.Chat, c.ThreadID)
+	// Replying keeps the panel inside the forum topic
+	if c.ThreadID != 0 {
+		cfg.ReplyToMessageID = msg.MessageID
+	}
+	if _, err := c.Bot.Send(cfg); err != nil {
+		logError("settingsPanel", c.Bot.Self.UserName, err)
+	}
+}
+
+func settingsText(lang string, chat *tgbotapi.Chat, threadID int) string {
+	status := T(lang, "settings.on")
+	if isGroup(chat) && !areTopicReactionsEnabled(chat.ID, threadID) {
+		status = T(lang, "settings.off")
+	}
+	if threadID != 0 {
+		status += T(lang, "settings.in_topic")
+	}
+	return T(lang, "settings.panel", status, getPalette(chat.ID).Label, getRate(chat.ID),
+		quietLabel(lang, getQuietHours(chat.ID)), getReactionMode(chat.ID))
+}
+
+func quietLabel(lang string, q quietHours) string {
+	if !q.Enabled() {
+		return T(lang, "settings.quiet_off")
+	}
+	return q.String()
+}
+
+func settingsKeyboard(lang string, chat *tgbotapi.Chat, threadID int) tgbotapi.InlineKeyboardMarkup {
+	var rows [][]tgbotapi.InlineKeyboardButton
+	if isGroup(chat) {
+		label := T(lang, "settings.btn_on")
+		if !areTopicReactionsEnabled(chat.ID, threadID) {
+			label = T(lang, "settings.btn_off")
+		}
+		if threadID != 0 {
+			label += T(lang, "settings.in_topic")
+		}
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(label, settingsPrefix+"toggle"),
+		))
+	}
+	rows = append(rows,
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(T(lang, "settings.btn_palette", getPalette(chat.ID).Label), settingsPrefix+"palettes"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(T(lang, "settings.btn_rate", getRate(chat.ID)), settingsPrefix+"rate"),
+			tgbotapi.NewInlineKeyboardButtonData(T(lang, "settings.btn_quiet", quietLabel(lang, getQuietHours(chat.ID))), settingsPrefix+"quiet"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(T(lang, "settings.btn_mode", getReactionMode(chat.ID)), settingsPrefix+"mode"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(T(lang, "settings.btn_close"), settingsPrefix+"close"),
+		),
+	)
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+func paletteKeyboard(lang string, chatID int64) tgbotapi.InlineKeyboardMarkup {
+	current := getPalette(chatID).Name
+	var rows [][]tgbotapi.InlineKeyboardButton
+	for _, p := range palettes {
+		label := p.Label
+		if p.Name == current {
+			label = "• " + label + " •"
+		}
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(label, settingsPrefix+"palette:"+p.Name),
+		))
+	}
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(T(lang, "btn.back"), settingsPrefix+"main"),
+	))
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+// ─── Settings Callbacks ──────────────────
+func handleSettingsCallback(bot *tgbotapi.BotAPI, cb *tgbotapi.CallbackQuery, threadID int) {
+	msg := cb.Message
+	chat := msg.Chat
+	lang := langFor(&tgbotapi.Message{Chat: chat, From: cb.From})
+
+	if isGroup(chat) && !isOpenToggle(chat.ID) && !isUserAdmin(bot, chat.ID, cb.From.ID) {
+		answerCallback(bot, cb.ID, T(lang, "settings.admins_only"))
+		return
+	}
+
+	action := strings.TrimPrefix(cb.Data, settingsPrefix)
+	value := ""
+	if i := strings.Index(action, ":"); i >= 0 {
+		action, value = action[:i], action[i+1:]
+	}
+	log.Printf(ColorBlue+"⚙️  Settings %s %s by @%s in %d"+ColorReset, action, value, cb.From.UserName, chat.ID)
+
+	switch action {
+	case "toggle":
+		setTopicReactionsEnabled(chat.ID, threadID, !areTopicReactionsEnabled(chat.ID, threadID))
+	case "palettes":
+		answerCallback(bot, cb.ID, "")
+		editSettingsPanel(bot, msg, T(lang, "settings.choose_palette"), paletteKeyboard(lang, chat.ID))
+		return
+	case "palette":
+		if _, ok := findPalette(value); ok {
+			setPalette(chat.ID, value)
+		}
+	case "rate":
+		setRate(chat.ID, nextRate(getRate(chat.ID)))
+	case "quiet":
+		setQuietHours(chat.ID, nextQuietHours(getQuietHours(chat.ID)))
+	case "mode":
+		if getReactionMode(chat.ID) == ModeMirror {
+			setReactionMode(chat.ID, ModeNormal)
+		} else {
+			setReactionMode(chat.ID, ModeMirror)
+		}
+	case "close":
+		answerCallback(bot, cb.ID, "")
+		if _, err := bot.Request(tgbotapi.NewDeleteMessage(chat.ID, msg.MessageID)); err != nil {
+			logError("settingsClose", bot.Self.UserName, err)
+		}
+		return
+	case "main":
+	default:
+		answerCallback(bot, cb.ID, T(lang, "settings.unknown"))
+		return
+	}
+
+	answerCallback(bot, cb.ID, T(lang, "settings.saved"))
+	editSettingsPanel(bot, msg, settingsText(lang, chat, threadID), settingsKeyboard(lang, chat, threadID))
+}
+
+func nextRate(current int) int {
+	for i, r := range rateSteps {
+		if r == current {
+			return rateSteps[(i+1)%len(rateSteps)]
+		}
+	}
+	return rateSteps[0]
+}
+
+func nextQuietHours(current quietHours) quietHours {
+	for i, q := range quietSteps {
+		if q == current {
+			return quietSteps[(i+1)%len(quietSteps)]
+		}
+	}
+	return quietSteps[0]
+}
+
+func editSettingsPanel(bot *tgbotapi.BotAPI, msg *tgbotapi.Message, text string, kb tgbotapi.InlineKeyboardMarkup) {
+	edit := tgbotapi.NewEditMessageTextAndMarkup(msg.Chat.ID, msg.MessageID, text, kb)
+	edit.ParseMode = "HTML"
+	if _, err := bot.Send(edit); err != nil {
+		logError("settingsEdit", bot.Self.UserName, err)
+	}
+}
+
+func answerCallback(bot *tgbotapi.BotAPI, id, text string) {
+	if _, err := bot.Request(tgbotapi.NewCallback(id, text)); err != nil {
+		logError("answerCallback", bot.Self.UserName, err)
+	}
+}
@@ -0,0 +1,274 @@
+package main
+
+import (
+	"fmt"
+	"html"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// ─── Chat Stats ──────────────────────────
+type emojiCount struct {
+	Emoji string
+	Count int
+}
+
+type dayCount struct {
+	Day   string // YYYY-MM-DD
+	Count int
+}
+
+type messageCount struct {
+	MessageID int
+	Count     int
+}
+
+type chatStats struct {
+	Total       int
+	TopEmojis   []emojiCount
+	LastWeek    []dayCount
+	TopMessages []messageCount
+}
+
+// loadChatStats reads everything /stats shows for one chat. All queries
+// filter on chat_id first so they stay on idx_reactions_chat_time or the
+// message_reactions primary key.
+func loadChatStats(chatID int64) (chatStats, error) {
+	var st chatStats
+
+	if err := db.QueryRow(`SELECT COUNT(*) FROM reactions WHERE chat_id = ?`, chatID).Scan(&st.Total); err != nil {
+		return st, fmt.Errorf("count reactions: %w", err)
+	}
+
+	rows, err := db.Query(`SELECT emoji, COUNT(*) AS n FROM reactions
+		WHERE chat_id = ? GROUP BY emoji ORDER BY n DESC LIMIT 5`, chatID)
+	if err != nil {
+		return st, fmt.Errorf("top emojis: %w", err)
+	}
+	for rows.Next() {
+		var ec emojiCount
+		if err := rows.Scan(&ec.Emoji, &ec.Count); err != nil {
+			rows.Close()
+			return st, fmt.Errorf("scan top emoji: %w", err)
+		}
+		st.TopEmojis = append(st.TopEmojis, ec)
+	}
+	rows.Close()
+
+	rows, err = db.Query(`SELECT date(timestamp) AS day, COUNT(*) FROM reactions
+		WHERE chat_id = ? AND timestamp >= datetime('now', 'start of day', '-6 days')
+		GROUP BY day ORDER BY day`, chatID)
+	if err != nil {
+		return st, fmt.Errorf("reactions per day: %w", err)
+	}
+	perDay := make(map[string]int)
+	for rows.Next() {
+		var day string
+		var n int
+		if err := rows.Scan(&day, &n); err != nil {
+			rows.Close()
+			return st, fmt.Errorf("scan day: %w", err)
+		}
+		perDay[day] = n
+	}
+	rows.Close()
+	// Fill in the days without reactions so the week always has 7 rows
+	today := time.Now().UTC()
+	for i := 6; i >= 0; i-- {
+		day := today.AddDate(0, 0, -i).Format("2006-01-02")
+		st.LastWeek = append(st.LastWeek, dayCount{Day: day, Count: perDay[day]})
+	}
+
+	// Ranked by what people put on the message, see recordHumanReaction
+	rows, err = db.Query(`SELECT message_id, SUM(count) AS n FROM message_reactions
+		WHERE chat_id = ? GROUP BY message_id ORDER BY n DESC, message_id DESC LIMIT 3`, chatID)
+	if err != nil {
+		return st, fmt.Errorf("top messages: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var mc messageCount
+		if err := rows.Scan(&mc.MessageID, &mc.Count); err != nil {
+			return st, fmt.Errorf("scan top message: %w", err)
+		}
+		st.TopMessages = append(st.TopMessages, mc)
+	}
+	return st, rows.Err()
+}
+
+// ─── Human Reactions ─────────────────────
+// message_reactions keeps how many reactions each person left on a
+// message, keyed by actor_id. Every bot in the chat receives the same
+// update, so rows are overwritten rather than incremented. Messages with
+// anonymous reactions only get a total, stored under actor_id 0.
+func recordHumanReaction(upd *messageReactionUpdated) {
+	var actorID int64
+	switch {
+	case upd.User != nil && !upd.User.IsBot:
+		actorID = upd.User.ID
+	case upd.User == nil && upd.ActorChat != nil:
+		actorID = upd.ActorChat.ID
+	default:
+		return
+	}
+	storeMessageReactions(upd.Chat.ID, upd.MessageID, actorID, len(upd.NewReaction))
+}
+
+func recordReactionCount(upd *messageReactionCountUpdated) {
+	total := 0
+	for _, r := range upd.Reactions {
+		total += r.TotalCount
+	}
+	storeMessageReactions(upd.Chat.ID, upd.MessageID, 0, total)
+}
+
+func storeMessageReactions(chatID int64, msgID int, actorID int64, count int) {
+	var err error
+	if count == 0 {
+		_, err = db.Exec(`DELETE FROM message_reactions WHERE chat_id = ? AND message_id = ? AND actor_id = ?`,
+			chatID, msgID, actorID)
+	} else {
+		_, err = db.Exec(`INSERT INTO message_reactions (chat_id, message_id, actor_id, count) VALUES (?, ?, ?, ?)
+			ON CONFLICT(chat_id, message_id, actor_id) DO UPDATE SET count = excluded.count`,
+			chatID, msgID, actorID, count)
+	}
+	if err != nil {
+		logError("storeMessageReactions", fmt.Sprint(chatID), err)
+	}
+}
+
+// ─── /stats Command ──────────────────────
+func handleStatsCommand(bot *tgbotapi.BotAPI, msg *tgbotapi.Message) {
+	log.Printf(ColorBlue+"📊 /stats by @%s in %d"+ColorReset, msg.From.UserName, msg.Chat.ID)
+	lang := langFor(msg)
+
+	st, err := loadChatStats(msg.Chat.ID)
+	if err != nil {
+		logError("loadChatStats", bot.Self.UserName, err)
+		cfg := tgbotapi.NewMessage(msg.Chat.ID, T(lang, "stats.error"))
+		if _, err := bot.Send(cfg); err != nil {
+			logError("statsError", bot.Self.UserName, err)
+		}
+		return
+	}
+
+	cfg := tgbotapi.NewMessage(msg.Chat.ID, formatChatStats(lang, msg.Chat, st))
+	cfg.ParseMode = "HTML"
+	cfg.DisableWebPagePreview = true
+	if _, err := bot.Send(cfg); err != nil {
+		logError("statsSend", bot.Self.UserName, err)
+	}
+}
+
+func formatChatStats(lang string, chat *tgbotapi.Chat, st chatStats) string {
+	if st.Total == 0 {
+		return T(lang, "stats.empty")
+	}
+
+	var b strings.Builder
+	b.WriteString(T(lang, "stats.title", html.EscapeString(chat.Title)) + "\n\n")
+	b.WriteString(T(lang, "stats.total", st.Total) + "\n\n")
+
+	b.WriteString(T(lang, "stats.top_emojis") + "\n")
+	for i, ec := range st.TopEmojis {
+		fmt.Fprintf(&b, "%d. %s × %d\n", i+1, ec.Emoji, ec.Count)
+	}
+
+	b.WriteString("\n" + T(lang, "stats.last_week") + "\n")
+	for _, dc := range st.LastWeek {
+		fmt.Fprintf(&b, "<code>%s</code> %d\n", dc.Day[5:], dc.Count)
+	}
+
+	if len(st.TopMessages) == 0 {
+		return b.String()
+	}
+	b.WriteString("\n" + T(lang, "stats.top_messages") + "\n")
+	for i, mc := range st.TopMessages {
+		label := T(lang, "stats.message", mc.MessageID)
+		if link := messageLink(chat, mc.MessageID); link != "" {
+			fmt.Fprintf(&b, "%d. <a href=\"%s\">%s</a> × %d\n", i+1, link, label, mc.Count)
+		} else {
+			fmt.Fprintf(&b, "%d. %s × %d\n", i+1, label, mc.Count)
+		}
+	}
+	return b.String()
+}
+
+// messageLink builds a t.me link to a message. Only supergroups have
+// linkable messages.
+func messageLink(chat *tgbotapi.Chat, msgID int) string {
+	if chat.UserName != "" {
+		return fmt.Sprintf("https://t.me/%s/%d", chat.UserName, msgID)
+	}
+	id := strconv.FormatInt(chat.ID, 10)
+	if chat.Type == "supergroup" && strings.HasPrefix(id, "-100") {
+		return fmt.Sprintf("https://t.me/c/%s/%d", strings.TrimPrefix(id, "-100"), msgID)
+	}
+	return ""
+}
+
+// ─── User Stats ──────────────────────────
+type userStats struct {
+	Received int
+	Favorite string
+	Rank     int // 1-based position among members who received reactions
+	Ranked   int // number of members in the ranking
+}
+
+func loadUserStats(chatID, userID int64) (userStats, error) {
+	var us userStats
+
+	if err := db.QueryRow(`SELECT COUNT(*) FROM reactions WHERE chat_id = ? AND user_id = ?`,
+		chatID, userID).Scan(&us.Received); err != nil {
+		return us, fmt.Errorf("count user reactions: %w", err)
+	}
+	if us.Received == 0 {
+		return us, nil
+	}
+
+	if err := db.QueryRow(`SELECT emoji FROM reactions WHERE chat_id = ? AND user_id = ?
+		GROUP BY emoji ORDER BY COUNT(*) DESC LIMIT 1`, chatID, userID).Scan(&us.Favorite); err != nil {
+		return us, fmt.Errorf("favorite emoji: %w", err)
+	}
+
+	if err := db.QueryRow(`SELECT
+			COUNT(*) FILTER (WHERE n > ?) + 1,
+			COUNT(*)
+		FROM (SELECT COUNT(*) AS n FROM reactions
+			WHERE chat_id = ? AND user_id IS NOT NULL GROUP BY user_id)`,
+		us.Received, chatID).Scan(&us.Rank, &us.Ranked); err != nil {
+		return us, fmt.Errorf("user rank: %w", err)
+	}
+	return us, nil
+}
+
+// ─── /mystats Command ────────────────────
+func handleMyStatsCommand(bot *tgbotapi.BotAPI, msg *tgbotapi.Message) {
+	log.Printf(ColorBlue+"📊 /mystats by @%s in %d"+ColorReset, msg.From.UserName, msg.Chat.ID)
+	lang := langFor(msg)
+
+	cfg := tgbotapi.NewMessage(msg.Chat.ID, "")
+	cfg.ParseMode = "HTML"
+	cfg.ReplyToMessageID = msg.MessageID
+
+	us, err := loadUserStats(msg.Chat.ID, msg.From.ID)
+	switch {
+	case err != nil:
+		logError("loadUserStats", bot.Self.UserName, err)
+		cfg.Text = T(lang, "mystats.error")
+		cfg.ParseMode = ""
+	case us.Received == 0:
+		cfg.Text = T(lang, "mystats.empty")
+	default:
+		cfg.Text = T(lang, "mystats.body",
+			html.EscapeString(msg.From.FirstName), us.Received, us.Favorite, us.Rank, us.Ranked)
+	}
+
+	if _, err := bot.Send(cfg); err != nil {
+		logError("mystatsSend", bot.Self.UserName, err)
+	}
+}
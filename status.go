@@ -0,0 +1,148 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// ─── Poll Health ─────────────────────────
+// pollUpdates reports every getUpdates call here so /status can tell a
+// healthy bot from one that keeps failing.
+var (
+	startedAt  = time.Now()
+	pollStates = make(map[int64]*pollState) // botID -> state
+	pollMutex  sync.Mutex                   // protects pollStates
+)
+
+type pollState struct {
+	lastOK   time.Time
+	lastErr  error
+	failures int // consecutive failed polls
+}
+
+func trackPolling(botID int64) {
+	pollMutex.Lock()
+	defer pollMutex.Unlock()
+	pollStates[botID] = &pollState{}
+}
+
+func recordPoll(botID int64, err error) {
+	pollMutex.Lock()
+	defer pollMutex.Unlock()
+	s, ok := pollStates[botID]
+	if !ok {
+		return
+	}
+	if err != nil {
+		s.lastErr = err
+		s.failures++
+		return
+	}
+	s.lastOK = time.Now()
+	s.failures = 0
+}
+
+// botState describes a bot's polling in one line.
+func botState(botID int64) string {
+	pollMutex.Lock()
+	defer pollMutex.Unlock()
+	s, ok := pollStates[botID]
+	switch {
+	case !ok:
+		return "⚪ not polling"
+	case s.failures > 0:
+		return fmt.Sprintf("🟡 retrying, %d failures: %s", s.failures, redactToken(s.lastErr.Error()))
+	case s.lastOK.IsZero():
+		return "⚪ starting"
+	case time.Since(s.lastOK) > time.Minute:
+		return "🔴 stalled since " + s.lastOK.UTC().Format("15:04:05")
+	default:
+		return "🟢 polling"
+	}
+}
+
+// ─── Status Command ──────────────────────
+// Anyone may check that the bot is alive; the per-bot section names the
+// whole fleet, so only the owner sees it, and only in private.
+func handleStatusCommand(c *commandContext) {
+	log.Println(ColorBlue + "🩺 /status command received" + ColorReset)
+
+	// SQLite: a small indexed query like the ones stats run
+	dbStart := time.Now()
+	var lastHour int
+	dbLine := ""
+	if err := db.QueryRow(`SELECT COUNT(*) FROM reactions WHERE timestamp >= datetime('now', '-1 hour')`).Scan(&lastHour); err != nil {
+		logError("statusDB", c.Bot.Self.UserName, err)
+		dbLine = "❌ " + redactToken(err.Error())
+	} else {
+		dbLine = fmt.Sprintf("%s, %d reactions in the last hour", formatLatency(time.Since(dbStart)), lastHour)
+	}
+
+	mirrorMutex.Lock()
+	pendingMirrors := len(pendingMirror)
+	mirrorMutex.Unlock()
+	jobs, deliveries := broadcastsInFlight()
+	bots := currentBots()
+
+	var b strings.Builder
+	b.WriteString("🩺 *Status*\n\n")
+	fmt.Fprintf(&b, "⏱️ Uptime: %s\n", escapeMarkdownV2(formatUptime(time.Since(startedAt))))
+	fmt.Fprintf(&b, "🧵 Goroutines: %d\n", runtime.NumGoroutine())
+	fmt.Fprintf(&b, "🗄️ SQLite: %s\n", escapeMarkdownV2(dbLine))
+	fmt.Fprintf(&b, "📤 Outbound: %d mirror reactions, %d broadcasts with %d deliveries left\n", pendingMirrors, jobs, deliveries)
+	fmt.Fprintf(&b, "🤖 Bots: %d\n", len(bots))
+
+	if c.Msg.From.ID == ownerID && c.Msg.Chat.IsPrivate() {
+		b.WriteString("\n")
+		for _, bot := range bots {
+			// getMe is the cheapest call that goes all the way to Telegram
+			apiStart := time.Now()
+			api := ""
+			if _, err := bot.GetMe(); err != nil {
+				api = "❌ " + redactToken(err.Error())
+			} else {
+				api = formatLatency(time.Since(apiStart))
+			}
+			fmt.Fprintf(&b, "• @%s\n   %s\n   📡 API: %s\n",
+				escapeMarkdownV2(bot.Self.UserName), escapeMarkdownV2(botState(bot.Self.ID)), escapeMarkdownV2(api))
+		}
+	}
+
+	cfg := tgbotapi.NewMessage(c.Msg.Chat.ID, b.String())
+	cfg.ParseMode = "MarkdownV2"
+	if isGroup(c.Msg.Chat) {
+		cfg.ReplyToMessageID = c.Msg.MessageID
+	}
+	if _, err := c.Bot.Send(cfg); err != nil {
+		logError("statusCommand", c.Bot.Self.UserName, err)
+	}
+}
+
+// ─── Token Redaction ─────────────────────
+// Request errors quote the API URL, and with it the bot token.
+var tokenPattern = regexp.MustCompile(`\d+:[A-Za-z0-9_-]{30,}`)
+
+func redactToken(s string) string {
+	return tokenPattern.ReplaceAllString(s, "<token>")
+}
+
+func formatLatency(d time.Duration) string {
+	return fmt.Sprintf("%.2fms", float64(d.Microseconds())/1000)
+}
+
+func formatUptime(d time.Duration) string {
+	d = d.Truncate(time.Minute)
+	days := d / (24 * time.Hour)
+	d -= days * 24 * time.Hour
+	if days > 0 {
+		return fmt.Sprintf("%dd %dh %dm", days, d/time.Hour, (d%time.Hour)/time.Minute)
+	}
+	return fmt.Sprintf("%dh %dm", d/time.Hour, (d%time.Hour)/time.Minute)
+}
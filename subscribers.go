@@ -0,0 +1,207 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// ─── Subscribers ─────────────────────────
+// Every chat the bots hear from is a subscriber. The map is the working
+// copy; the subscribers and subscriber_bots tables keep it across
+// restarts. Writes are batched per chat so a busy group does not cost a
+// database write per message.
+var subscriberFlush = 5 * time.Minute
+
+// subscriber is what we remember about a chat we can broadcast to.
+type subscriber struct {
+	Type      string
+	Title     string
+	Inactive  bool // no bot is left in the chat
+	FirstSeen time.Time
+	LastSeen  time.Time
+	Bots      map[int64]bool // IDs of the bots present in the chat
+	Lang      string         // Telegram language of a private chat's user, "" if unknown
+
+	stored time.Time // last write to the database
+}
+
+// addSubscriber records that bot heard from chat and returns the
+// subscriber count. from is the sender, if any; in private chats their
+// Telegram language is kept for lang= audiences.
+func addSubscriber(botID int64, chat *tgbotapi.Chat, from *tgbotapi.User) int {
+	now := time.Now()
+	lang := ""
+	if chat.IsPrivate() && from != nil {
+		lang = normalizeLang(from.LanguageCode)
+	}
+
+	subMutex.Lock()
+	sub, known := subscribers[chat.ID]
+	dirty := !known || sub.Inactive || sub.Type != chat.Type || sub.Title != chat.Title ||
+		!sub.Bots[botID] || (lang != "" && lang != sub.Lang) || now.Sub(sub.stored) > subscriberFlush
+	if !known {
+		sub.FirstSeen = now
+		sub.Bots = make(map[int64]bool)
+	}
+	sub.Type, sub.Title, sub.Inactive, sub.LastSeen = chat.Type, chat.Title, false, now
+	sub.Bots[botID] = true
+	if lang != "" {
+		sub.Lang = lang
+	}
+	if dirty {
+		sub.stored = now
+	}
+	subscribers[chat.ID] = sub
+	total := len(subscribers)
+	subMutex.Unlock()
+
+	if dirty {
+		if err := storeSubscriber(botID, chat, sub.Lang, now); err != nil {
+			logError("storeSubscriber", fmt.Sprint(chat.ID), err)
+		}
+	}
+	return total
+}
+
+func storeSubscriber(botID int64, chat *tgbotapi.Chat, lang string, now time.Time) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("begin: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`INSERT INTO subscribers (chat_id, type, title, first_seen, last_seen, inactive, lang) VALUES (?, ?, ?, ?, ?, 0, ?)
+		ON CONFLICT (chat_id) DO UPDATE SET type = excluded.type, title = excluded.title,
+			last_seen = excluded.last_seen, inactive = 0, lang = excluded.lang`,
+		chat.ID, chat.Type, chat.Title, now, now, lang)
+	if err != nil {
+		return fmt.Errorf("upsert subscriber: %w", err)
+	}
+	_, err = tx.Exec(`INSERT INTO subscriber_bots (chat_id, bot_id, first_seen, last_seen) VALUES (?, ?, ?, ?)
+		ON CONFLICT (chat_id, bot_id) DO UPDATE SET last_seen = excluded.last_seen`,
+		chat.ID, botID, now, now)
+	if err != nil {
+		return fmt.Errorf("upsert subscriber bot: %w", err)
+	}
+	return tx.Commit()
+}
+
+// removeSubscriberBot records that bot left chatID or was blocked there.
+// Once no bot is left the chat stays on record but drops out of
+// broadcasts until it shows up again.
+func removeSubscriberBot(botID, chatID int64) {
+	subMutex.Lock()
+	sub, ok := subscribers[chatID]
+	if ok {
+		delete(sub.Bots, botID)
+		sub.Inactive = len(sub.Bots) == 0
+		subscribers[chatID] = sub
+	}
+	subMutex.Unlock()
+	if !ok {
+		return
+	}
+
+	if _, err := db.Exec(`DELETE FROM subscriber_bots WHERE chat_id = ? AND bot_id = ?`, chatID, botID); err != nil {
+		logError("removeSubscriberBot", fmt.Sprint(chatID), err)
+	}
+	if sub.Inactive {
+		if _, err := db.Exec(`UPDATE subscribers SET inactive = 1 WHERE chat_id = ?`, chatID); err != nil {
+			logError("deactivateSubscriber", fmt.Sprint(chatID), err)
+		}
+		log.Printf(ColorYellow+"💤 No bots left in %d, dropped from broadcasts"+ColorReset, chatID)
+	}
+}
+
+// memberBots returns the bots present in chatID, in botInstances order.
+func memberBots(chatID int64, bots []*tgbotapi.BotAPI) []*tgbotapi.BotAPI {
+	subMutex.RLock()
+	members := subscribers[chatID].Bots
+	var out []*tgbotapi.BotAPI
+	for _, bot := range bots {
+		if members[bot.Self.ID] {
+			out = append(out, bot)
+		}
+	}
+	subMutex.RUnlock()
+	return out
+}
+
+// isMembershipError reports whether a send failed because the bot is no
+// longer in the chat: kicked, blocked, or the chat is gone.
+func isMembershipError(err error) bool {
+	var tgErr *tgbotapi.Error
+	if !errors.As(err, &tgErr) {
+		return false
+	}
+	return tgErr.Code == 403 || (tgErr.Code == 400 && strings.Contains(tgErr.Message, "chat not found"))
+}
+
+// subscriberIDs returns the active subscribers as a snapshot, so callers
+// can send without holding subMutex.
+func subscriberIDs() []int64 {
+	subMutex.RLock()
+	defer subMutex.RUnlock()
+	ids := make([]int64, 0, len(subscribers))
+	for id, sub := range subscribers {
+		if !sub.Inactive {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// loadSubscribers fills the map from the database at startup.
+func loadSubscribers() error {
+	rows, err := db.Query(`SELECT chat_id, type, title, first_seen, last_seen, inactive, lang FROM subscribers`)
+	if err != nil {
+		return fmt.Errorf("query subscribers: %w", err)
+	}
+	defer rows.Close()
+
+	loaded := make(map[int64]subscriber)
+	for rows.Next() {
+		var (
+			id  int64
+			sub subscriber
+		)
+		if err := rows.Scan(&id, &sub.Type, &sub.Title, &sub.FirstSeen, &sub.LastSeen, &sub.Inactive, &sub.Lang); err != nil {
+			return fmt.Errorf("scan subscriber: %w", err)
+		}
+		sub.Bots = make(map[int64]bool)
+		sub.stored = sub.LastSeen
+		loaded[id] = sub
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("query subscribers: %w", err)
+	}
+
+	botRows, err := db.Query(`SELECT chat_id, bot_id FROM subscriber_bots`)
+	if err != nil {
+		return fmt.Errorf("query subscriber bots: %w", err)
+	}
+	defer botRows.Close()
+	for botRows.Next() {
+		var chatID, botID int64
+		if err := botRows.Scan(&chatID, &botID); err != nil {
+			return fmt.Errorf("scan subscriber bot: %w", err)
+		}
+		if sub, ok := loaded[chatID]; ok {
+			sub.Bots[botID] = true
+		}
+	}
+	if err := botRows.Err(); err != nil {
+		return fmt.Errorf("query subscriber bots: %w", err)
+	}
+
+	subMutex.Lock()
+	subscribers = loaded
+	subMutex.Unlock()
+	log.Printf(ColorGreen+"👥 Loaded %d subscribers"+ColorReset, len(loaded))
+	return nil
+}
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"html"
+	"io/fs"
+	"log"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// ─── Message Templates ───────────────────
+// Templates live in <dir>/<lang>/<name>.<ext>. The extension picks the
+// parse mode: .html for HTML, .md2 for MarkdownV2, .txt for plain text.
+// The built-in set is embedded; files in TEMPLATES_DIR override it and
+// are reloaded when they change.
+
+//go:embed templates
+var builtinTemplates embed.FS
+
+type messageTemplate struct {
+	Text      string
+	ParseMode string
+}
+
+var (
+	templateDir    = getEnv("TEMPLATES_DIR", "./templates")
+	templates      = make(map[string]messageTemplate) // "lang/name" -> template
+	templateMutex  sync.RWMutex                       // protects templates
+	templateReload = 15 * time.Second
+)
+
+var parseModes = map[string]string{
+	".html": "HTML",
+	".md2":  "MarkdownV2",
+	".txt":  "",
+}
+
+// loadTemplates rebuilds the template set from the embedded defaults and
+// the on-disk overrides, then swaps it in.
+func loadTemplates() error {
+	loaded := make(map[string]messageTemplate)
+
+	embedded, err := fs.Sub(builtinTemplates, "templates")
+	if err != nil {
+		return fmt.Errorf("embedded templates: %w", err)
+	}
+	if err := readTemplates(embedded, loaded); err != nil {
+		return fmt.Errorf("embedded templates: %w", err)
+	}
+
+	if info, err := os.Stat(templateDir); err == nil && info.IsDir() {
+		if err := readTemplates(os.DirFS(templateDir), loaded); err != nil {
+			return fmt.Errorf("templates in %s: %w", templateDir, err)
+		}
+	}
+
+	templateMutex.Lock()
+	templates = loaded
+	templateMutex.Unlock()
+	log.Printf(ColorGreen+"📝 Loaded %d message templates"+ColorReset, len(loaded))
+	return nil
+}
+
+func readTemplates(fsys fs.FS, into map[string]messageTemplate) error {
+	return fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		mode, ok := parseModes[path.Ext(p)]
+		if !ok {
+			return nil
+		}
+		lang, file := path.Split(p)
+		lang = strings.Trim(lang, "/")
+		if lang == "" {
+			return nil
+		}
+		data, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			return err
+		}
+		name := strings.TrimSuffix(file, path.Ext(file))
+		into[lang+"/"+name] = messageTemplate{Text: strings.TrimRight(string(data), "\n"), ParseMode: mode}
+		return nil
+	})
+}
+
+// watchTemplates polls TEMPLATES_DIR and reloads when any file changes.
+func watchTemplates(ctx context.Context) {
+	last := templatesModTime()
+	ticker := time.NewTicker(templateReload)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			mod := templatesModTime()
+			if mod.Equal(last) {
+				continue
+			}
+			last = mod
+			log.Println(ColorCyan + "🔄 Templates changed, reloading..." + ColorReset)
+			if err := loadTemplates(); err != nil {
+				logError("loadTemplates", templateDir, err)
+			}
+		}
+	}
+}
+
+// templatesModTime returns the newest modification time in TEMPLATES_DIR,
+// counting directories so deletions are noticed too.
+func templatesModTime() time.Time {
+	var newest time.Time
+	fs.WalkDir(os.DirFS(templateDir), ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info, err := d.Info(); err == nil && info.ModTime().After(newest) {
+			newest = info.ModTime()
+		}
+		return nil
+	})
+	return newest
+}
+
+// ─── Template Rendering ──────────────────
+// templateData is what a template can refer to. Extra holds additional
+// {key} values; all values are escaped for the template's parse mode.
+type templateData struct {
+	Chat  *tgbotapi.Chat
+	User  *tgbotapi.User
+	Bot   *tgbotapi.BotAPI
+	Extra map[string]string
+}
+
+// renderTemplate fills in the named template for lang, falling back to
+// defaultLang, and returns the text together with its parse mode.
+func renderTemplate(name, lang string, data templateData) (string, string) {
+	templateMutex.RLock()
+	tmpl, ok := templates[lang+"/"+name]
+	if !ok {
+		tmpl, ok = templates[defaultLang+"/"+name]
+	}
+	templateMutex.RUnlock()
+	if !ok {
+		log.Printf(ColorRed+"⚠️  Missing template %q"+ColorReset, name)
+		return name, ""
+	}
+
+	esc := escaperFor(tmpl.ParseMode)
+	var pairs []string
+	if data.Chat != nil {
+		pairs = append(pairs, "{chat_title}", esc(data.Chat.Title))
+	}
+	if data.User != nil {
+		pairs = append(pairs, "{user_mention}", mentionFor(tmpl.ParseMode, data.User))
+	}
+	if data.Bot != nil {
+		pairs = append(pairs, "{bot_username}", esc(data.Bot.Self.UserName))
+	}
+	for k, v := range data.Extra {
+		pairs = append(pairs, "{"+k+"}", esc(v))
+	}
+	return strings.NewReplacer(pairs...).Replace(tmpl.Text), tmpl.ParseMode
+}
+
+// escaperFor returns the escaping function for a Telegram parse mode.
+func escaperFor(parseMode string) func(string) string {
+	switch parseMode {
+	case "HTML":
+		return html.EscapeString
+	case "MarkdownV2":
+		return escapeMarkdownV2
+	default:
+		return func(s string) string { return s }
+	}
+}
+
+// mentionFor links to a user in the given parse mode. Plain text cannot
+// link, so it falls back to the name.
+func mentionFor(parseMode string, user *tgbotapi.User) string {
+	name := strings.TrimSpace(user.FirstName + " " + user.LastName)
+	if name == "" {
+		name = user.UserName
+	}
+	link := "tg://user?id=" + strconv.FormatInt(user.ID, 10)
+	switch parseMode {
+	case "HTML":
+		return fmt.Sprintf(`<a href="%s">%s</a>`, link, html.EscapeString(name))
+	case "MarkdownV2":
+		return fmt.Sprintf("[%s](%s)", escapeMarkdownV2(name), link)
+	default:
+		return name
+	}
+}
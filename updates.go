@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// ─── Raw Updates ─────────────────────────
+// botUpdate wraps the library update with fields that the pinned
+// tgbotapi version does not decode yet.
+type botUpdate struct {
+	tgbotapi.Update
+	MessageReaction      *messageReactionUpdated
+	MessageReactionCount *messageReactionCountUpdated
+	ThreadID             int // forum topic of Message or of CallbackQuery's message, 0 outside topics
+}
+
+// rawUpdateExtras holds the update fields missing from tgbotapi.Update.
+type rawUpdateExtras struct {
+	Message              *rawMessageExtras            `json:"message,omitempty"`
+	MessageReaction      *messageReactionUpdated      `json:"message_reaction,omitempty"`
+	MessageReactionCount *messageReactionCountUpdated `json:"message_reaction_count,omitempty"`
+	CallbackQuery        *rawCallbackExtras           `json:"callback_query,omitempty"`
+}
+
+type rawCallbackExtras struct {
+	Message *rawMessageExtras `json:"message,omitempty"`
+}
+
+type rawMessageExtras struct {
+	MessageThreadID int  `json:"message_thread_id,omitempty"`
+	IsTopicMessage  bool `json:"is_topic_message,omitempty"`
+}
+
+type messageReactionUpdated struct {
+	Chat        tgbotapi.Chat  `json:"chat"`
+	MessageID   int            `json:"message_id"`
+	User        *tgbotapi.User `json:"user,omitempty"`
+	ActorChat   *tgbotapi.Chat `json:"actor_chat,omitempty"`
+	Date        int            `json:"date"`
+	OldReaction []reactionType `json:"old_reaction"`
+	NewReaction []reactionType `json:"new_reaction"`
+}
+
+// messageReactionCountUpdated is sent instead of messageReactionUpdated
+// for messages with anonymous reactions, e.g. channel posts.
+type messageReactionCountUpdated struct {
+	Chat      tgbotapi.Chat   `json:"chat"`
+	MessageID int             `json:"message_id"`
+	Date      int             `json:"date"`
+	Reactions []reactionCount `json:"reactions"`
+}
+
+type reactionCount struct {
+	Type       reactionType `json:"type"`
+	TotalCount int          `json:"total_count"`
+}
+
+type reactionType struct {
+	Type          string `json:"type"`
+	Emoji         string `json:"emoji,omitempty"`
+	CustomEmojiID string `json:"custom_emoji_id,omitempty"`
+}
+
+// pollUpdates long-polls getUpdates like GetUpdatesChan, but decodes each
+// update twice so the extra fields survive.
+func pollUpdates(ctx context.Context, bot *tgbotapi.BotAPI, config tgbotapi.UpdateConfig) <-chan botUpdate {
+	ch := make(chan botUpdate, bot.Buffer)
+	trackPolling(bot.Self.ID)
+
+	go func() {
+		defer close(ch)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			default:
+			}
+
+			resp, err := bot.Request(config)
+			recordPoll(bot.Self.ID, err)
+			if err != nil {
+				logError("getUpdates", bot.Self.UserName, err)
+				incrementFailure(bot.Self.UserName)
+				time.Sleep(3 * time.Second)
+				continue
+			}
+
+			var raws []json.RawMessage
+			if err := json.Unmarshal(resp.Result, &raws); err != nil {
+				logError("decode updates", bot.Self.UserName, err)
+				continue
+			}
+
+			for _, raw := range raws {
+				// Move past every update, even one we fail to decode
+				// below, or getUpdates keeps returning it
+				var id struct {
+					UpdateID int `json:"update_id"`
+				}
+				if err := json.Unmarshal(raw, &id); err != nil {
+					logError("decode update id", bot.Self.UserName, err)
+					continue
+				}
+				if id.UpdateID < config.Offset {
+					continue
+				}
+				config.Offset = id.UpdateID + 1
+
+				var upd botUpdate
+				if err := json.Unmarshal(raw, &upd.Update); err != nil {
+					logError("decode update", bot.Self.UserName, err)
+					continue
+				}
+				var extras rawUpdateExtras
+				if err := json.Unmarshal(raw, &extras); err != nil {
+					log.Printf(ColorYellow+"⚠️  Ignoring unknown fields in update %d: %v"+ColorReset, upd.UpdateID, err)
+				}
+				upd.MessageReaction = extras.MessageReaction
+				upd.MessageReactionCount = extras.MessageReactionCount
+				// message_thread_id is also set on plain reply threads, so
+				// only trust it for real forum topics.
+				msgExtras := extras.Message
+				if extras.CallbackQuery != nil {
+					msgExtras = extras.CallbackQuery.Message
+				}
+				if msgExtras != nil && msgExtras.IsTopicMessage {
+					upd.ThreadID = msgExtras.MessageThreadID
+				}
+
+				select {
+				case ch <- upd:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return ch
+}